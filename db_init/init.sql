@@ -2,12 +2,20 @@ USE collab;
 
 CREATE TABLE stories (
     id int NOT NULL AUTO_INCREMENT,
-    title varchar(32) DEFAULT '' NOT NULL, 
+    room varchar(32) DEFAULT 'default' NOT NULL, 
+    title varchar(256) DEFAULT '' NOT NULL, -- up to 6 words (StoryRules.TitleWords) of up to 36 chars
     titleAdded tinyint(1) DEFAULT 0,
     isFinished tinyint(1) DEFAULT 0, 
     createdAt datetime DEFAULT CURRENT_TIMESTAMP NOT NULL, 
     updatedAt datetime DEFAULT CURRENT_TIMESTAMP NOT NULL, 
-    PRIMARY KEY (id)
+    state varchar(16) DEFAULT 'collecting_title' NOT NULL, -- collecting_title, writing, finished, archived or hidden
+    version int DEFAULT 0 NOT NULL, -- incremented on every change to Story's content
+    parent int DEFAULT 0 NOT NULL, -- story this one was forked from (0 if none)
+    prompt varchar(256) DEFAULT '' NOT NULL, -- prompt or theme the story was seeded with
+    keywords varchar(512) DEFAULT '' NOT NULL, -- comma separated theme keywords
+    PRIMARY KEY (id),
+    INDEX (room, isFinished), 
+    INDEX (parent)
 );
 
 CREATE TABLE paragraphs (
@@ -21,6 +29,79 @@ CREATE TABLE sentences (
     id int NOT NULL AUTO_INCREMENT, 
     paragraph int NOT NULL, 
     isFinished tinyint(1) DEFAULT 0, 
-    content varchar(240) DEFAULT '', -- 15 chars in 15 words + 14 spaces in between ~ 240
+    content varchar(2048) DEFAULT '', -- up to 50 words (StoryRules.SentenceWords) of up to 36 chars (32 runes + punctuation)
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE idempotency_keys (
+    room varchar(32) NOT NULL,
+    idemKey varchar(255) NOT NULL, -- unique per Room
+    requestHash char(64) NOT NULL, -- hex SHA-256 of the request body
+    response text NOT NULL, -- JSON encoded WordResponse
+    createdAt datetime DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (room, idemKey)
+);
+
+CREATE TABLE rooms (
+    name varchar(32) NOT NULL, 
+    titleWords int NOT NULL, 
+    sentenceWords int NOT NULL, 
+    minSentenceWords int NOT NULL, -- 0 disables ending Sentences early with punctuation
+    paragraphSentences int NOT NULL, 
+    storyParagraphs int NOT NULL, 
+    voteSeconds int DEFAULT 0 NOT NULL, -- 0 adds words right away, otherwise next word is chosen by vote
+    prompt varchar(256) DEFAULT '' NOT NULL, -- chosen by an admin for the room's next story (used once)
+    keywords varchar(512) DEFAULT '' NOT NULL, -- comma separated theme keywords of the prompt
+    PRIMARY KEY (name)
+);
+
+CREATE TABLE rejected_words (
+    id int NOT NULL AUTO_INCREMENT, 
+    room varchar(32) NOT NULL, 
+    word varchar(64) NOT NULL, 
+    filter varchar(32) NOT NULL, -- which WordFilter rejected the word
+    reason varchar(576) NOT NULL, -- up to a theme's keywords (512 chars) with a message
+    createdAt datetime DEFAULT CURRENT_TIMESTAMP NOT NULL, 
     PRIMARY KEY (id)
-);
\ No newline at end of file
+);
+
+CREATE TABLE audit_log (
+    id int NOT NULL AUTO_INCREMENT, 
+    actor varchar(64) NOT NULL, 
+    action varchar(32) NOT NULL, -- replace_word, delete_word, redact_sentence or rename_story
+    storyId int NOT NULL, 
+    sentenceId int DEFAULT 0 NOT NULL, -- 0 for title changes
+    oldContent varchar(2048) NOT NULL, 
+    newContent varchar(2048) NOT NULL, 
+    createdAt datetime DEFAULT CURRENT_TIMESTAMP NOT NULL, 
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE vote_rounds (
+    id int NOT NULL AUTO_INCREMENT, 
+    room varchar(32) NOT NULL, 
+    isClosed tinyint(1) DEFAULT 0, 
+    winner int DEFAULT 0 NOT NULL, -- winning candidate (0 if none was added)
+    story int DEFAULT 0 NOT NULL, -- story the winning word was added to
+    endsAt datetime NOT NULL, 
+    createdAt datetime DEFAULT CURRENT_TIMESTAMP NOT NULL, 
+    PRIMARY KEY (id), 
+    INDEX (room, isClosed)
+);
+
+CREATE TABLE vote_candidates (
+    id int NOT NULL AUTO_INCREMENT, 
+    round int NOT NULL, 
+    word varchar(64) NOT NULL, 
+    createdAt datetime DEFAULT CURRENT_TIMESTAMP NOT NULL, 
+    PRIMARY KEY (id), 
+    UNIQUE (round, word)
+);
+
+CREATE TABLE votes (
+    round int NOT NULL, 
+    candidate int NOT NULL, 
+    voter varchar(64) NOT NULL, 
+    createdAt datetime DEFAULT CURRENT_TIMESTAMP NOT NULL, 
+    PRIMARY KEY (round, voter) -- one vote per voter per round
+);
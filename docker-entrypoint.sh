@@ -4,7 +4,7 @@ echo "Waiting for DB to start..."
 
 cd collab 
 
-./wait-for database:8080 -- echo "Database Has Started..."
+./wait-for database:3306 -- echo "Database Has Started..."
 # https://github.com/eficode/wait-for
 
 # Make sure server binary is copies onto prodution container /collab 
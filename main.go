@@ -1,19 +1,37 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	mux "github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shubhamdwivedii/collab-story/pkg/cache"
+	"github.com/shubhamdwivedii/collab-story/pkg/config"
+	"github.com/shubhamdwivedii/collab-story/pkg/health"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+
 	mw "github.com/shubhamdwivedii/collab-story/pkg/middlewares"
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
 	sv "github.com/shubhamdwivedii/collab-story/pkg/server"
+	mem "github.com/shubhamdwivedii/collab-story/pkg/storage/memory"
 	st "github.com/shubhamdwivedii/collab-story/pkg/storage/mysql"
 	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
 	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
 )
 
@@ -21,53 +39,291 @@ var (
 	logger *logrus.Logger
 )
 
-func init() {
-	logger = logrus.New()
-	file, err := os.OpenFile("logs.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+// Usage:
+//
+//	server [flags]                 serves requests (flags, env variables and the -config file are listed by -help)
+//	server config print [flags]    prints the effective config as YAML, without secrets
+//	server healthcheck [flags]     exits with 0 if the running server is ready, 1 otherwise (for Docker's HEALTHCHECK)
+func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "healthcheck" {
+		os.Exit(healthcheck(args[1:]))
+	}
+	if len(args) > 1 && args[0] == "config" && args[1] == "print" {
+		os.Exit(printConfig(args[2:]))
+	}
+
+	cfg, err := config.Load(args, os.LookupEnv)
 	if err != nil {
-		logger.Fatal(err.Error())
-	}
-	// debugMode := "TruE"
-	debugMode := os.Getenv("LOGS_ENABLE")
-	mw := io.MultiWriter(os.Stdout, file)
-	logger.Out = mw
-	logger.SetFormatter(&logrus.TextFormatter{
-		DisableColors: false,
-		ForceColors:   true,
-		FullTimestamp: false,
-	})
+		os.Exit(configError(err))
+	}
+	var logFile io.Closer
+	if logger, logFile, err = logging.NewLogger(cfg.Log.Logging()); err != nil {
+		fmt.Fprintln(os.Stderr, "Could Not Open Log File:", err)
+		os.Exit(1)
+	}
 
-	if debugMode == "1" || strings.ToLower(debugMode) == "true" {
-		logger.Level = logrus.DebugLevel
+	err = serve(cfg)
+	if err != nil {
+		logger.WithError(err).Error("Server Stopped")
 	} else {
-		logger.Level = logrus.FatalLevel
+		logger.Info("Server Stopped, All Requests Completed")
+	}
+	if logFile != nil {
+		logFile.Close()
+	}
+	if err != nil {
+		os.Exit(1)
 	}
 }
 
-func main() {
-	DB_URL := os.Getenv("DB_URL")
-	// DB_URL := "root:admin@tcp(127.0.0.1:3306)/collab"
-	storage, err := st.NewMySQLStorage(DB_URL, logger)
+// Prints why the config could not be loaded, returns the exit code.
+func configError(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	fmt.Fprintln(os.Stderr, err)
+	return 2
+}
+
+func printConfig(args []string) int {
+	cfg, err := config.Load(args, os.LookupEnv)
+	var invalid *config.ValidationError
+	if err != nil && !errors.As(err, &invalid) {
+		return configError(err)
+	}
+	out, yamlErr := cfg.Redacted().YAML()
+	if yamlErr != nil {
+		fmt.Fprintln(os.Stderr, yamlErr)
+		return 1
+	}
+	os.Stdout.Write(out)
+	if err != nil {
+		return configError(err) // printed anyway, to show what was wrong
+	}
+	return 0
+}
+
+// Requests /readyz of the server running with the same config (or $HEALTHCHECK_URL).
+func healthcheck(args []string) int {
+	url := os.Getenv("HEALTHCHECK_URL")
+	if url == "" {
+		cfg, err := config.Load(args, os.LookupEnv)
+		var invalid *config.ValidationError
+		if err != nil && !errors.As(err, &invalid) {
+			return configError(err)
+		}
+		url = readyURL(cfg.Server)
+	}
+	if err := health.Probe(url, 5*time.Second); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// URL of /readyz on the local server listening on cfg.Address.
+func readyURL(cfg config.ServerConfig) string {
+	host, port, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		return "http://localhost:8080/readyz"
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	scheme := "http"
+	if cfg.TLS.Enabled() {
+		scheme = "https"
+	}
+	return scheme + "://" + net.JoinHostPort(host, port) + "/readyz"
+}
+
+// Serves requests until SIGINT or SIGTERM. Deferred Stop and Close calls run once requests are drained,
+// so nothing is cut off mid-transaction.
+func serve(cfg *config.Config) error {
+	storage, err := st.NewMySQLStorage(cfg.DB.URL, logger)
 	if err != nil {
-		logger.Fatal(err)
+		return err
 	}
+	defer storage.Close() // last, after requests and background workers are done with it
+	storage.SetPool(cfg.DB.MaxOpenConns, cfg.DB.MaxIdleConns, cfg.DB.ConnMaxLifetime.Duration, cfg.DB.ConnMaxIdleTime.Duration)
 
 	wordService := wrd.NewWordService(storage, logger)
-	storyService := str.NewStoryService(storage, logger)
+	wordService.SetDefaultRules(cfg.Stories.Rules)
+	registry := prometheus.NewRegistry()
+	wordService.EnableMetrics(registry)
+	registry.MustRegister(collectors.NewDBStatsCollector(storage.DB(), "collab"))
+
+	features := cfg.Features
+	if features.IdempotencyStorage == "memory" {
+		wordService.EnableIdempotency(mem.NewIdempotencyStorage(), features.IdempotencyWindow.Duration)
+	} else {
+		wordService.EnableIdempotency(storage, features.IdempotencyWindow.Duration)
+	}
+	wordService.EnableUndo(features.UndoWindow.Duration)
+	wordService.EnableSlots(features.SlotTTL.Duration)
+	wordService.EnableVoting(storage)
+
+	var filters moderation.Chain
+	if features.BlocklistFile != "" {
+		words, err := moderation.LoadWordList(features.BlocklistFile)
+		if err != nil {
+			return fmt.Errorf("Invalid features.blocklist_file: %w", err)
+		}
+		filters = append(filters, moderation.NewBlocklist(words))
+	}
+	if features.AllowlistFile != "" {
+		words, err := moderation.LoadWordList(features.AllowlistFile)
+		if err != nil {
+			return fmt.Errorf("Invalid features.allowlist_file: %w", err)
+		}
+		filters = append(filters, moderation.NewAllowlist(words))
+	}
+	files, _ := features.Dictionaries() // validated
+	var dictionaries moderation.Dictionaries
+	for _, file := range files {
+		dictionary, err := moderation.NewDictionary(file.Language, file.Path)
+		if err != nil {
+			return fmt.Errorf("Invalid features.dictionary_files: %w", err)
+		}
+		dictionaries = append(dictionaries, dictionary)
+	}
+	if len(dictionaries) > 0 {
+		filters = append(filters, dictionaries)
+	}
+	if features.ThemeFilter {
+		filters = append(filters, moderation.NewThemeFilter())
+	}
+	if len(filters) > 0 {
+		wordService.EnableModeration(filters, storage)
+	}
+	wordService.EnableAudit(storage)
+
+	if cfg.Stories.PromptsFile != "" {
+		prompts, err := str.LoadPrompts(cfg.Stories.PromptsFile)
+		if err != nil {
+			return fmt.Errorf("Invalid stories.prompts_file: %w", err)
+		}
+		wordService.SetPrompts(prompts)
+	}
+
+	var storyStorage str.StoryStorage = storage
+	if cfg.Stories.CacheSize > 0 {
+		cachingStorage := str.NewCachingStoryStorage(storage, cache.NewLRUCache(cfg.Stories.CacheSize), cfg.Stories.CacheTTL.Duration, logger)
+		wordService.AddInvalidator(cachingStorage)
+		storyStorage = cachingStorage
+	}
+	storyService := str.NewStoryService(storyStorage, logger)
+	storyService.SetDefaultRules(cfg.Stories.Rules)
+
+	// Already validated, so parsing cannot fail.
+	trustedProxies, _ := mw.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	limiter := mw.NewRateLimiter(mw.NewMemoryRateLimitStore(), trustedProxies, logger)
+	addLimits, _ := mw.ParseRouteLimits(features.RateLimits.Add)
+	batchLimits, _ := mw.ParseRouteLimits(features.RateLimits.Batch)
+	voteLimits, _ := mw.ParseRouteLimits(features.RateLimits.Vote)
+	forkLimits, _ := mw.ParseRouteLimits(features.RateLimits.Fork)
+
 	router := mux.NewRouter()
+	router.Use(mw.RequestMetrics(registry))
+	if features.Tracing.Exporter == "otlp" {
+		provider, err := tracing.NewOTLPProvider(context.Background(), features.Tracing.Endpoint, "collab-story")
+		if err != nil {
+			return err
+		}
+		defer provider.Shutdown(context.Background()) // sends the spans of drained requests
+		router.Use(mw.Tracing(provider))
+	}
+	router.Use(mw.RequestID())
+	router.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{})).Methods("GET")
+
+	checker := health.NewChecker(2 * time.Second)
+	checker.AddCheck("db", storage.Ping)
+	checker.AddCheck("schema", storage.CheckSchema)
+	checker.AddCheck("room_locks", func(ctx context.Context) error {
+		return wordService.CheckLockWait(ctx, cfg.Server.ReadyMaxLockWait.Duration)
+	})
+	router.HandleFunc("/healthz", health.LivenessHandler).Methods("GET")
+	router.HandleFunc("/readyz", checker.ReadinessHandler).Methods("GET")
 
 	server, err := sv.NewServer(wordService, storyService, logger)
+	if err != nil {
+		return err
+	}
+	server.SetPageSize(cfg.Stories.PageSize)
+	server.SetDictionaries(dictionaries)
+	server.SetClientIP(limiter.ClientIP)
 
-	router.HandleFunc("/add", mw.DurationLogger(server.AddWordHandler, logger)).Methods("POST")
+	router.HandleFunc("/add", mw.DurationLogger(limiter.Limit(server.AddWordHandler, "add", addLimits), logger)).Methods("POST")
+	// Batches also draw a token per Word from the add bucket, so they allow no more Words than single adds.
+	addWords := limiter.LimitCost(server.AddWordsHandler, "add", addLimits, sv.BatchCost)
+	router.HandleFunc("/add/batch", mw.DurationLogger(limiter.Limit(addWords, "batch", batchLimits), logger)).Methods("POST")
+	router.HandleFunc("/add/{word}", mw.DurationLogger(server.UndoWordHandler, logger)).Methods("DELETE")
 	router.HandleFunc("/stories", mw.DurationLogger(server.GetStoriesHandler, logger)).Methods("GET")
 	router.HandleFunc("/stories/{story}", mw.DurationLogger(server.GetStoryHandler, logger)).Methods("GET")
+	router.HandleFunc("/stories/{story}/fork", mw.DurationLogger(limiter.Limit(server.ForkStoryHandler, "fork", forkLimits), logger)).Methods("POST")
+	router.HandleFunc("/stories/{story}/forks", mw.DurationLogger(server.GetForksHandler, logger)).Methods("GET")
+	router.HandleFunc("/slots", mw.DurationLogger(limiter.Limit(server.ReserveSlotHandler, "slots", addLimits), logger)).Methods("POST")
+	router.HandleFunc("/round", mw.DurationLogger(server.GetOpenRoundHandler, logger)).Methods("GET")
+	router.HandleFunc("/rounds/{round}", mw.DurationLogger(server.GetRoundHandler, logger)).Methods("GET")
+	router.HandleFunc("/rounds/{round}/votes", mw.DurationLogger(limiter.Limit(server.VoteHandler, "vote", voteLimits), logger)).Methods("POST")
+	router.HandleFunc("/rooms/{room}", mw.DurationLogger(server.GetRoomHandler, logger)).Methods("GET")
+	router.HandleFunc("/rooms/{room}/add", mw.DurationLogger(limiter.Limit(server.AddWordHandler, "add", addLimits), logger)).Methods("POST")
+	router.HandleFunc("/rooms/{room}/add/batch", mw.DurationLogger(limiter.Limit(addWords, "batch", batchLimits), logger)).Methods("POST")
+	router.HandleFunc("/rooms/{room}/add/{word}", mw.DurationLogger(server.UndoWordHandler, logger)).Methods("DELETE")
+	router.HandleFunc("/rooms/{room}/slots", mw.DurationLogger(limiter.Limit(server.ReserveSlotHandler, "slots", addLimits), logger)).Methods("POST")
+	router.HandleFunc("/rooms/{room}/round", mw.DurationLogger(server.GetOpenRoundHandler, logger)).Methods("GET")
+
+	adminToken := cfg.Server.AdminToken
+	router.HandleFunc("/admin/stories", mw.DurationLogger(mw.AdminOnly(server.GetAdminStoriesHandler, adminToken), logger)).Methods("GET")
+	router.HandleFunc("/admin/stories/{story}/state", mw.DurationLogger(mw.AdminOnly(server.UpdateStoryStateHandler, adminToken), logger)).Methods("POST")
+	router.HandleFunc("/stories/{story}/finish", mw.DurationLogger(mw.AdminOnly(server.FinishStoryHandler, adminToken), logger)).Methods("POST")
+	router.HandleFunc("/admin/rooms/{room}", mw.DurationLogger(mw.AdminOnly(server.UpdateRoomHandler, adminToken), logger)).Methods("PUT")
+	router.HandleFunc("/admin/rooms/{room}/prompt", mw.DurationLogger(mw.AdminOnly(server.SetRoomPromptHandler, adminToken), logger)).Methods("PUT")
+	router.HandleFunc("/admin/rejected-words", mw.DurationLogger(mw.AdminOnly(server.GetRejectedWordsHandler, adminToken), logger)).Methods("GET")
+	router.HandleFunc("/admin/dictionaries/reload", mw.DurationLogger(mw.AdminOnly(server.ReloadDictionariesHandler, adminToken), logger)).Methods("POST")
+	router.HandleFunc("/admin/stories/{story}/sentences", mw.DurationLogger(mw.AdminOnly(server.GetStorySentencesHandler, adminToken), logger)).Methods("GET")
+	router.HandleFunc("/admin/stories/{story}/title", mw.DurationLogger(mw.AdminOnly(server.RenameStoryHandler, adminToken), logger)).Methods("PUT")
+	router.HandleFunc("/admin/sentences/{sentence}/words/{index}", mw.DurationLogger(mw.AdminOnly(server.ReplaceWordHandler, adminToken), logger)).Methods("PUT")
+	router.HandleFunc("/admin/sentences/{sentence}/words/{index}", mw.DurationLogger(mw.AdminOnly(server.DeleteWordHandler, adminToken), logger)).Methods("DELETE")
+	router.HandleFunc("/admin/sentences/{sentence}/redact", mw.DurationLogger(mw.AdminOnly(server.RedactSentenceHandler, adminToken), logger)).Methods("POST")
+	router.HandleFunc("/admin/audit", mw.DurationLogger(mw.AdminOnly(server.GetAuditEntriesHandler, adminToken), logger)).Methods("GET")
+
+	if cfg.Stories.IdleTimeout.Duration > 0 {
+		idleState := str.StateFinished
+		if cfg.Stories.IdleAction == "archive" {
+			idleState = str.StateArchived // Abandon idle Stories instead.
+		}
+		idleFinisher := wrd.NewIdleFinisher(wordService, cfg.Stories.IdleTimeout.Duration, idleState, logger)
+		idleFinisher.Start()
+		defer idleFinisher.Stop()
+	}
+
+	voteCloser := wrd.NewVoteCloser(wordService, time.Second, logger)
+	voteCloser.Start()
+	defer voteCloser.Stop()
 
 	httpServer := &http.Server{
 		Handler:      router,
-		Addr:         ":8080",
-		WriteTimeout: 15 * time.Second,
-		ReadTimeout:  15 * time.Second,
+		Addr:         cfg.Server.Address,
+		WriteTimeout: cfg.Server.WriteTimeout.Duration,
+		ReadTimeout:  cfg.Server.ReadTimeout.Duration,
 	}
 
-	logger.Fatal(httpServer.ListenAndServe())
+	listener, err := net.Listen("tcp", httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	if cfg.Server.TLS.Enabled() {
+		cert, err := tls.LoadX509KeyPair(cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile)
+		if err != nil {
+			listener.Close()
+			return fmt.Errorf("Invalid server.tls: %w", err)
+		}
+		httpServer.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	logger.Info("Listening On ", httpServer.Addr)
+	return sv.Serve(ctx, httpServer, listener, cfg.Server.ShutdownTimeout.Duration)
 }
@@ -0,0 +1,13 @@
+package cache
+
+import "time"
+
+// A Key/Value Cache. Values are stored as bytes so that out of process
+// implementations (e.g. Redis) can be plugged in instead of the in-process LRU.
+type Cache interface {
+	// Returns false if key is missing (or has expired).
+	Get(key string) ([]byte, bool)
+	// A ttl of 0 means value never expires (but may still be evicted).
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(keys ...string)
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero if entry never expires
+}
+
+// In-Process Cache evicting Least Recently Used entries once size is reached.
+type LRUCache struct {
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+	sync.Mutex
+}
+
+func NewLRUCache(size int) *LRUCache {
+	c := new(LRUCache)
+	c.size = size
+	c.entries = make(map[string]*list.Element)
+	c.order = list.New()
+	return c
+}
+
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	defer c.Unlock()
+	c.Lock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	defer c.Unlock()
+	c.Lock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRUCache) Delete(keys ...string) {
+	defer c.Unlock()
+	c.Lock()
+
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.remove(elem)
+		}
+	}
+}
+
+// Number of entries currently in Cache (including expired ones not yet removed).
+func (c *LRUCache) Len() int {
+	defer c.Unlock()
+	c.Lock()
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*lruEntry).key)
+}
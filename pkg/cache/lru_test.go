@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheEviction(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", []byte("1"), 0)
+	c.Set("b", []byte("2"), 0)
+
+	// Using "a" makes "b" the least recently used.
+	_, ok := c.Get("a")
+	assert.True(t, ok, "Expected Key To Be Cached")
+
+	c.Set("c", []byte("3"), 0)
+	_, ok = c.Get("b")
+	assert.False(t, ok, "Expected Least Recently Used Key To Be Evicted")
+
+	value, ok := c.Get("a")
+	assert.True(t, ok, "Expected Key To Be Cached")
+	assert.Equal(t, "1", string(value))
+	assert.Equal(t, 2, c.Len())
+
+	c.Delete("a", "c")
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", []byte("1"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok, "Expected Expired Key To Be Missing")
+	assert.Equal(t, 0, c.Len())
+}
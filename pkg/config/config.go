@@ -0,0 +1,458 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	mw "github.com/shubhamdwivedii/collab-story/pkg/middlewares"
+	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// A time.Duration written as "15s" in YAML (instead of nanoseconds).
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	duration, err := time.ParseDuration(value.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	d.Duration = duration
+	return nil
+}
+
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	DB       DBConfig       `yaml:"db"`
+	Log      LogConfig      `yaml:"log"`
+	Stories  StoriesConfig  `yaml:"stories"`
+	Features FeaturesConfig `yaml:"features"`
+}
+
+type ServerConfig struct {
+	Address         string    `yaml:"address"`
+	ReadTimeout     Duration  `yaml:"read_timeout"`
+	WriteTimeout    Duration  `yaml:"write_timeout"`
+	ShutdownTimeout Duration  `yaml:"shutdown_timeout"` // to drain in-flight requests
+	TLS             TLSConfig `yaml:"tls"`
+	AdminToken      string    `yaml:"admin_token"`     // empty disables admin endpoints
+	TrustedProxies  string    `yaml:"trusted_proxies"` // comma separated CIDRs
+	// Not ready while a Word has been waiting this long for a Room's lock.
+	ReadyMaxLockWait Duration `yaml:"ready_max_lock_wait"`
+}
+
+// Serves HTTPS if both files are set.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
+
+type DBConfig struct {
+	URL             string   `yaml:"url"`            // e.g. user:password@tcp(host:3306)/collab
+	MaxOpenConns    int      `yaml:"max_open_conns"` // 0 for unlimited
+	MaxIdleConns    int      `yaml:"max_idle_conns"`
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime"`  // 0 to reuse connections forever
+	ConnMaxIdleTime Duration `yaml:"conn_max_idle_time"` // 0 to keep idle connections forever
+}
+
+type LogConfig struct {
+	Level      string `yaml:"level"`  // debug, info, warn, error or fatal
+	Format     string `yaml:"format"` // text or json
+	File       string `yaml:"file"`   // empty for stdout only
+	MaxSizeMB  int    `yaml:"max_size_mb"`
+	MaxBackups int    `yaml:"max_backups"`
+}
+
+func (l LogConfig) Logging() logging.Config {
+	return logging.Config{Level: l.Level, Format: l.Format, File: l.File, MaxSize: int64(l.MaxSizeMB) << 20, MaxBackups: l.MaxBackups}
+}
+
+type StoriesConfig struct {
+	Rules       str.StoryRules `yaml:"rules"`      // of Rooms never configured by an admin
+	PageSize    int32          `yaml:"page_size"`  // when a listing has no limit
+	CacheSize   int            `yaml:"cache_size"` // 0 disables caching
+	CacheTTL    Duration       `yaml:"cache_ttl"`
+	IdleTimeout Duration       `yaml:"idle_timeout"` // 0 never closes idle Stories
+	IdleAction  string         `yaml:"idle_action"`  // finish or archive
+	PromptsFile string         `yaml:"prompts_file"`
+}
+
+type FeaturesConfig struct {
+	IdempotencyWindow  Duration         `yaml:"idempotency_window"`
+	IdempotencyStorage string           `yaml:"idempotency_storage"` // mysql or memory
+	UndoWindow         Duration         `yaml:"undo_window"`         // 0 disables undo
+	SlotTTL            Duration         `yaml:"slot_ttl"`            // 0 disables slots
+	BlocklistFile      string           `yaml:"blocklist_file"`
+	AllowlistFile      string           `yaml:"allowlist_file"`
+	ThemeFilter        bool             `yaml:"theme_filter"`
+	DictionaryFiles    string           `yaml:"dictionary_files"` // e.g. en=words/en.txt,fr=words/fr.txt
+	RateLimits         RateLimitsConfig `yaml:"rate_limits"`
+	Tracing            TracingConfig    `yaml:"tracing"`
+}
+
+// Per route, as parsed by middlewares.ParseRouteLimits (e.g. "ip=60/1m,contributor=20/1m" or "off").
+type RateLimitsConfig struct {
+	Add   string `yaml:"add"`
+	Batch string `yaml:"batch"`
+	Vote  string `yaml:"vote"`
+	Fork  string `yaml:"fork"`
+}
+
+type TracingConfig struct {
+	Exporter string `yaml:"exporter"` // empty disables tracing, or otlp
+	Endpoint string `yaml:"endpoint"`
+}
+
+func Default() *Config {
+	c := new(Config)
+	c.Server = ServerConfig{
+		Address:          ":8080",
+		ReadTimeout:      Duration{15 * time.Second},
+		WriteTimeout:     Duration{15 * time.Second},
+		ShutdownTimeout:  Duration{15 * time.Second},
+		ReadyMaxLockWait: Duration{5 * time.Second},
+	}
+	c.DB = DBConfig{MaxIdleConns: 2} // database/sql's default
+	c.Log = LogConfig{Level: "info", Format: "text", File: "logs.txt", MaxSizeMB: 100, MaxBackups: 5}
+	c.Stories = StoriesConfig{
+		Rules:      str.DefaultStoryRules,
+		PageSize:   10,
+		CacheSize:  1000,
+		CacheTTL:   Duration{5 * time.Minute},
+		IdleAction: "finish",
+	}
+	c.Features = FeaturesConfig{
+		IdempotencyWindow:  Duration{24 * time.Hour},
+		IdempotencyStorage: "mysql",
+		UndoWindow:         Duration{10 * time.Second},
+		SlotTTL:            Duration{5 * time.Second},
+		RateLimits: RateLimitsConfig{
+			Add:   "ip=60/1m,contributor=20/1m",
+			Batch: "ip=10/1m,contributor=5/1m",
+			Vote:  "ip=60/1m,contributor=30/1m",
+			Fork:  "ip=5/1m,contributor=2/1m",
+		},
+		Tracing: TracingConfig{Endpoint: "http://localhost:4318/v1/traces"},
+	}
+	return c
+}
+
+// Loads the config from defaults, then the YAML file (-config flag or $CONFIG_FILE), then env variables, then flags in args.
+// lookupEnv is os.LookupEnv outside of tests.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	c := Default()
+
+	path := configPath(args)
+	if path == "" {
+		path, _ = lookupEnv("CONFIG_FILE")
+	}
+	if path != "" {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(content, c); err != nil {
+			return nil, fmt.Errorf("Invalid Config File %s: %w", path, err)
+		}
+	}
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.String("config", path, "YAML config file ($CONFIG_FILE)")
+	b := &binder{fs: fs}
+	b.bind(c)
+
+	// LOGS_ENABLE predates LOG_LEVEL, it either logs everything or fatal errors only.
+	if debugMode, ok := lookupEnv("LOGS_ENABLE"); ok && debugMode != "" {
+		c.Log.Level = "fatal"
+		if debugMode == "1" || strings.ToLower(debugMode) == "true" {
+			c.Log.Level = "debug"
+		}
+	}
+	for _, env := range b.envs {
+		value, ok := lookupEnv(env.name)
+		if !ok || (value == "" && !env.allowEmpty) {
+			continue
+		}
+		if err := fs.Set(env.flag, value); err != nil {
+			return nil, fmt.Errorf("Invalid %s: %w", env.name, err)
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("Unexpected Argument %q", fs.Arg(0))
+	}
+	return c, c.Validate()
+}
+
+// Value of the -config flag in args, if any.
+func configPath(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name := strings.TrimLeft(arg, "-")
+		if name == "config" && i+1 < len(args) && arg != name {
+			return args[i+1]
+		}
+		if strings.HasPrefix(name, "config=") && arg != name {
+			return strings.TrimPrefix(name, "config=")
+		}
+	}
+	return ""
+}
+
+// An env variable setting a flag.
+type envBinding struct {
+	name       string
+	flag       string
+	allowEmpty bool // otherwise an empty variable is ignored
+}
+
+// Defines a flag (and env variable) for each setting, whose default is the setting's current value.
+type binder struct {
+	fs   *flag.FlagSet
+	envs []envBinding
+}
+
+func (b *binder) env(flag string, env string) {
+	b.envs = append(b.envs, envBinding{name: env, flag: flag})
+}
+
+func (b *binder) string(p *string, flag string, env string, usage string) {
+	b.fs.StringVar(p, flag, *p, usage+" ($"+env+")")
+	b.env(flag, env)
+}
+
+func (b *binder) int(p *int, flag string, env string, usage string) {
+	b.fs.IntVar(p, flag, *p, usage+" ($"+env+")")
+	b.env(flag, env)
+}
+
+func (b *binder) int32(p *int32, flag string, env string, usage string) {
+	b.fs.Var((*int32Value)(p), flag, usage+" ($"+env+")")
+	b.env(flag, env)
+}
+
+func (b *binder) bool(p *bool, flag string, env string, usage string) {
+	b.fs.BoolVar(p, flag, *p, usage+" ($"+env+")")
+	b.env(flag, env)
+}
+
+func (b *binder) duration(p *Duration, flag string, env string, usage string) {
+	b.fs.DurationVar(&p.Duration, flag, p.Duration, usage+" ($"+env+")")
+	b.env(flag, env)
+}
+
+func (b *binder) bind(c *Config) {
+	b.string(&c.Server.Address, "server.address", "SERVER_ADDRESS", "Address to listen on")
+	b.duration(&c.Server.ReadTimeout, "server.read_timeout", "READ_TIMEOUT", "Time to read a request")
+	b.duration(&c.Server.WriteTimeout, "server.write_timeout", "WRITE_TIMEOUT", "Time to write a response")
+	b.duration(&c.Server.ShutdownTimeout, "server.shutdown_timeout", "SHUTDOWN_TIMEOUT", "Time to drain in-flight requests on shutdown")
+	b.string(&c.Server.TLS.CertFile, "server.tls.cert_file", "TLS_CERT_FILE", "TLS certificate, serves HTTPS with key_file")
+	b.string(&c.Server.TLS.KeyFile, "server.tls.key_file", "TLS_KEY_FILE", "TLS private key")
+	b.string(&c.Server.AdminToken, "server.admin_token", "ADMIN_TOKEN", "Bearer token of admin endpoints, empty disables them")
+	b.string(&c.Server.TrustedProxies, "server.trusted_proxies", "TRUSTED_PROXIES", "Comma separated CIDRs of proxies whose X-Forwarded-For is trusted")
+	b.duration(&c.Server.ReadyMaxLockWait, "server.ready_max_lock_wait", "READY_MAX_LOCK_WAIT", "Not ready while a word waits this long for a room's lock")
+
+	b.string(&c.DB.URL, "db.url", "DB_URL", "MySQL DSN, e.g. user:password@tcp(host:3306)/collab")
+	b.int(&c.DB.MaxOpenConns, "db.max_open_conns", "DB_MAX_OPEN_CONNS", "Open connections, 0 for unlimited")
+	b.int(&c.DB.MaxIdleConns, "db.max_idle_conns", "DB_MAX_IDLE_CONNS", "Idle connections kept")
+	b.duration(&c.DB.ConnMaxLifetime, "db.conn_max_lifetime", "DB_CONN_MAX_LIFETIME", "Time a connection is reused, 0 for ever")
+	b.duration(&c.DB.ConnMaxIdleTime, "db.conn_max_idle_time", "DB_CONN_MAX_IDLE_TIME", "Time a connection is kept idle, 0 for ever")
+
+	b.string(&c.Log.Level, "log.level", "LOG_LEVEL", "debug, info, warn, error or fatal")
+	b.string(&c.Log.Format, "log.format", "LOG_FORMAT", "text or json")
+	b.string(&c.Log.File, "log.file", "LOG_FILE", "Log file (besides stdout), empty for stdout only")
+	b.envs[len(b.envs)-1].allowEmpty = true
+	b.int(&c.Log.MaxSizeMB, "log.max_size_mb", "LOG_MAX_SIZE_MB", "Size the log file is rotated at, 0 never rotates")
+	b.int(&c.Log.MaxBackups, "log.max_backups", "LOG_MAX_BACKUPS", "Rotated log files kept")
+
+	b.int32(&c.Stories.Rules.TitleWords, "stories.rules.title_words", "STORY_TITLE_WORDS", "Words in a title")
+	b.int32(&c.Stories.Rules.SentenceWords, "stories.rules.sentence_words", "STORY_SENTENCE_WORDS", "Most words in a sentence")
+	b.int32(&c.Stories.Rules.MinSentenceWords, "stories.rules.min_sentence_words", "STORY_MIN_SENTENCE_WORDS", "Words before punctuation can end a sentence, 0 disables")
+	b.int32(&c.Stories.Rules.ParagraphSentences, "stories.rules.paragraph_sentences", "STORY_PARAGRAPH_SENTENCES", "Sentences in a paragraph")
+	b.int32(&c.Stories.Rules.StoryParagraphs, "stories.rules.story_paragraphs", "STORY_PARAGRAPHS", "Paragraphs in a story")
+	b.int32(&c.Stories.Rules.VoteSeconds, "stories.rules.vote_seconds", "STORY_VOTE_SECONDS", "Length of voting rounds, 0 adds words right away")
+	b.int32(&c.Stories.PageSize, "stories.page_size", "PAGE_SIZE", "Stories listed when no limit is given")
+	b.int(&c.Stories.CacheSize, "stories.cache_size", "STORY_CACHE_SIZE", "Stories cached in memory, 0 disables caching")
+	b.duration(&c.Stories.CacheTTL, "stories.cache_ttl", "STORY_CACHE_TTL", "Time stories stay cached")
+	b.duration(&c.Stories.IdleTimeout, "stories.idle_timeout", "IDLE_TIMEOUT", "Close stories idle this long, 0 never does")
+	b.string(&c.Stories.IdleAction, "stories.idle_action", "IDLE_ACTION", "finish or archive idle stories")
+	b.string(&c.Stories.PromptsFile, "stories.prompts_file", "PROMPTS_FILE", "JSON prompts new stories are seeded with")
+
+	b.duration(&c.Features.IdempotencyWindow, "features.idempotency_window", "IDEMPOTENCY_WINDOW", "Time Idempotency-Key responses are replayed for")
+	b.string(&c.Features.IdempotencyStorage, "features.idempotency_storage", "IDEMPOTENCY_STORAGE", "mysql or memory")
+	b.duration(&c.Features.UndoWindow, "features.undo_window", "UNDO_WINDOW", "Time a word can be undone for, 0 disables undo")
+	b.duration(&c.Features.SlotTTL, "features.slot_ttl", "SLOT_TTL", "Time a reserved slot is held for, 0 disables slots")
+	b.string(&c.Features.BlocklistFile, "features.blocklist_file", "BLOCKLIST_FILE", "Blocked words, one per line")
+	b.string(&c.Features.AllowlistFile, "features.allowlist_file", "ALLOWLIST_FILE", "Only allowed words, one per line")
+	b.bool(&c.Features.ThemeFilter, "features.theme_filter", "THEME_FILTER", "Reject titles off their prompt's theme")
+	b.string(&c.Features.DictionaryFiles, "features.dictionary_files", "DICTIONARY_FILES", "Word lists, e.g. en=words/en.txt,fr=words/fr.txt")
+	b.string(&c.Features.RateLimits.Add, "features.rate_limits.add", "RATE_LIMIT_ADD", "Limits of adding words (batches cost a token per word) and reserving slots, or off")
+	b.string(&c.Features.RateLimits.Batch, "features.rate_limits.batch", "RATE_LIMIT_BATCH", "Limits of adding batches (on top of add), or off")
+	b.string(&c.Features.RateLimits.Vote, "features.rate_limits.vote", "RATE_LIMIT_VOTE", "Limits of voting, or off")
+	b.string(&c.Features.RateLimits.Fork, "features.rate_limits.fork", "RATE_LIMIT_FORK", "Limits of forking stories, or off")
+	b.string(&c.Features.Tracing.Exporter, "features.tracing.exporter", "TRACING_EXPORTER", "otlp, empty disables tracing")
+	b.string(&c.Features.Tracing.Endpoint, "features.tracing.endpoint", "OTEL_EXPORTER_OTLP_ENDPOINT", "OTLP/HTTP traces endpoint")
+}
+
+type int32Value int32
+
+func (v *int32Value) String() string {
+	if v == nil {
+		return "0"
+	}
+	return fmt.Sprint(int32(*v))
+}
+
+func (v *int32Value) Set(value string) error {
+	var parsed int32
+	if _, err := fmt.Sscan(value, &parsed); err != nil {
+		return errors.New("Invalid Number")
+	}
+	*v = int32Value(parsed)
+	return nil
+}
+
+// Every problem found in a Config.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "Invalid Config: " + strings.Join(e.Problems, "; ")
+}
+
+func (c *Config) Validate() error {
+	var problems []string
+	check := func(ok bool, problem string) {
+		if !ok {
+			problems = append(problems, problem)
+		}
+	}
+	checkErr := func(err error, setting string) {
+		if err != nil {
+			problems = append(problems, setting+": "+err.Error())
+		}
+	}
+
+	check(c.Server.Address != "", "server.address is required")
+	check(c.Server.ReadTimeout.Duration > 0, "server.read_timeout must be positive")
+	check(c.Server.WriteTimeout.Duration > 0, "server.write_timeout must be positive")
+	check(c.Server.ShutdownTimeout.Duration > 0, "server.shutdown_timeout must be positive")
+	check((c.Server.TLS.CertFile == "") == (c.Server.TLS.KeyFile == ""), "server.tls needs both cert_file and key_file")
+	_, err := mw.ParseTrustedProxies(c.Server.TrustedProxies)
+	checkErr(err, "server.trusted_proxies")
+	check(c.Server.ReadyMaxLockWait.Duration > 0, "server.ready_max_lock_wait must be positive")
+
+	check(c.DB.URL != "", "db.url is required")
+	check(c.DB.MaxOpenConns >= 0, "db.max_open_conns cannot be negative")
+	check(c.DB.MaxIdleConns >= 0, "db.max_idle_conns cannot be negative")
+	check(c.DB.ConnMaxLifetime.Duration >= 0, "db.conn_max_lifetime cannot be negative")
+	check(c.DB.ConnMaxIdleTime.Duration >= 0, "db.conn_max_idle_time cannot be negative")
+
+	_, err = log.ParseLevel(c.Log.Level)
+	checkErr(err, "log.level")
+	format := strings.ToLower(c.Log.Format)
+	check(format == "text" || format == "json", "log.format must be text or json")
+	check(c.Log.MaxSizeMB >= 0, "log.max_size_mb cannot be negative")
+	check(c.Log.MaxBackups >= 0, "log.max_backups cannot be negative")
+
+	checkErr(c.Stories.Rules.Validate(), "stories.rules")
+	check(c.Stories.PageSize > 0, "stories.page_size must be positive")
+	check(c.Stories.CacheSize >= 0, "stories.cache_size cannot be negative")
+	check(c.Stories.CacheSize == 0 || c.Stories.CacheTTL.Duration > 0, "stories.cache_ttl must be positive")
+	check(c.Stories.IdleTimeout.Duration >= 0, "stories.idle_timeout cannot be negative")
+	check(c.Stories.IdleAction == "finish" || c.Stories.IdleAction == "archive", "stories.idle_action must be finish or archive")
+
+	check(c.Features.IdempotencyWindow.Duration > 0, "features.idempotency_window must be positive")
+	storage := c.Features.IdempotencyStorage
+	check(storage == "mysql" || storage == "memory", "features.idempotency_storage must be mysql or memory")
+	check(c.Features.UndoWindow.Duration >= 0, "features.undo_window cannot be negative")
+	check(c.Features.SlotTTL.Duration >= 0, "features.slot_ttl cannot be negative")
+	_, err = c.Features.Dictionaries()
+	checkErr(err, "features.dictionary_files")
+	_, err = mw.ParseRouteLimits(c.Features.RateLimits.Add)
+	checkErr(err, "features.rate_limits.add")
+	_, err = mw.ParseRouteLimits(c.Features.RateLimits.Batch)
+	checkErr(err, "features.rate_limits.batch")
+	_, err = mw.ParseRouteLimits(c.Features.RateLimits.Vote)
+	checkErr(err, "features.rate_limits.vote")
+	_, err = mw.ParseRouteLimits(c.Features.RateLimits.Fork)
+	checkErr(err, "features.rate_limits.fork")
+	exporter := c.Features.Tracing.Exporter
+	check(exporter == "" || exporter == "otlp", "features.tracing.exporter must be otlp or empty")
+	check(exporter == "" || c.Features.Tracing.Endpoint != "", "features.tracing.endpoint is required")
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+type DictionaryFile struct {
+	Language string
+	Path     string
+}
+
+// Dictionary files (in order), from DictionaryFiles.
+func (f FeaturesConfig) Dictionaries() ([]DictionaryFile, error) {
+	if f.DictionaryFiles == "" {
+		return nil, nil
+	}
+	var dictionaries []DictionaryFile
+	for _, file := range strings.Split(f.DictionaryFiles, ",") {
+		parts := strings.SplitN(file, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, errors.New("Expected language=path, got " + file)
+		}
+		dictionaries = append(dictionaries, DictionaryFile{Language: strings.TrimSpace(parts[0]), Path: strings.TrimSpace(parts[1])})
+	}
+	return dictionaries, nil
+}
+
+const redacted = "<redacted>"
+
+// Copy of the Config safe to print, without the admin token and DB password.
+func (c Config) Redacted() Config {
+	if c.Server.AdminToken != "" {
+		c.Server.AdminToken = redacted
+	}
+	// DSN is [user[:password]@][protocol[(address)]]/dbname
+	if at := strings.LastIndex(c.DB.URL, "@"); at >= 0 {
+		if colon := strings.Index(c.DB.URL[:at], ":"); colon >= 0 {
+			c.DB.URL = c.DB.URL[:colon+1] + redacted + c.DB.URL[at:]
+		}
+	}
+	return c
+}
+
+func (c Config) YAML() ([]byte, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), encoder.Close()
+}
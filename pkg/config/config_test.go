@@ -0,0 +1,110 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+server:
+  address: ":9000"
+  read_timeout: 5s
+db:
+  url: root:admin@tcp(file)/collab
+stories:
+  page_size: 25
+  rules:
+    title_words: 3
+`)
+
+	cfg, err := Load([]string{"-config", path, "-server.address=:9100"}, env(map[string]string{
+		"DB_URL":       "root:admin@tcp(env)/collab",
+		"READ_TIMEOUT": "", // empty variables are ignored
+		"LOG_FILE":     "", // except for the log file, stdout only
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, ":9100", cfg.Server.Address, "Expected Flags To Override The File")
+	assert.Equal(t, "root:admin@tcp(env)/collab", cfg.DB.URL, "Expected Env To Override The File")
+	assert.Equal(t, 5*time.Second, cfg.Server.ReadTimeout.Duration)
+	assert.Equal(t, 15*time.Second, cfg.Server.WriteTimeout.Duration, "Expected Defaults For Missing Settings")
+	assert.Equal(t, int32(25), cfg.Stories.PageSize)
+	assert.Equal(t, int32(3), cfg.Stories.Rules.TitleWords)
+	assert.Equal(t, int32(15), cfg.Stories.Rules.SentenceWords)
+	assert.Equal(t, "", cfg.Log.File)
+
+	cfg, err = Load(nil, env(map[string]string{"CONFIG_FILE": path, "LOGS_ENABLE": "1", "THEME_FILTER": "1"}))
+	require.NoError(t, err)
+	assert.Equal(t, ":9000", cfg.Server.Address)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.True(t, cfg.Features.ThemeFilter)
+
+	_, err = Load([]string{"-stories.page_size=ten"}, env(map[string]string{"DB_URL": "x"}))
+	assert.Error(t, err)
+	_, err = Load(nil, env(map[string]string{"DB_URL": "x", "SLOT_TTL": "5"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid SLOT_TTL")
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Default()
+	cfg.DB.URL = "root:admin@tcp(database)/collab"
+	require.NoError(t, cfg.Validate())
+	cfg.Features.UndoWindow.Duration = 0
+	cfg.Features.SlotTTL.Duration = 0
+	require.NoError(t, cfg.Validate(), "Expected 0 To Disable Undo And Slots")
+
+	cfg.Server.TLS.CertFile = "cert.pem"
+	cfg.Stories.Rules.TitleWords = 0
+	cfg.Features.RateLimits.Add = "ip=60"
+	cfg.Features.DictionaryFiles = "words/en.txt"
+	cfg.Log.Level = "loud"
+	cfg.Features.SlotTTL.Duration = -time.Second
+	err := cfg.Validate()
+	var invalid *ValidationError
+	require.ErrorAs(t, err, &invalid)
+	assert.Len(t, invalid.Problems, 6)
+	assert.Contains(t, invalid.Problems, "server.tls needs both cert_file and key_file")
+}
+
+func TestRedactedYAML(t *testing.T) {
+	cfg := Default()
+	cfg.DB.URL = "root:s3cret@tcp(database:3306)/collab"
+	cfg.Server.AdminToken = "token"
+
+	out, err := cfg.Redacted().YAML()
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "s3cret")
+	assert.NotContains(t, string(out), "token: token")
+	assert.Contains(t, string(out), "url: root:<redacted>@tcp(database:3306)/collab")
+	assert.Equal(t, "root:s3cret@tcp(database:3306)/collab", cfg.DB.URL, "Expected The Config Itself To Be Unchanged")
+
+	// Printed configs can be loaded back.
+	out, err = cfg.YAML()
+	require.NoError(t, err)
+	loaded, err := Load([]string{"-config=" + writeConfig(t, string(out))}, env(nil))
+	require.NoError(t, err)
+	assert.Equal(t, cfg, loaded)
+}
@@ -0,0 +1,113 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Returns an error if a dependency (e.g. the DB) is not ready.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Runs the checks deciding whether the server is ready to take requests.
+type Checker struct {
+	checks  []namedCheck
+	timeout time.Duration
+}
+
+// Checks taking longer than timeout fail.
+func NewChecker(timeout time.Duration) *Checker {
+	checker := new(Checker)
+	checker.timeout = timeout
+	return checker
+}
+
+func (c *Checker) AddCheck(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+type Status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"` // "ok" or why a check failed, by name
+}
+
+// Runs all checks concurrently, returns whether they all passed.
+func (c *Checker) Run(ctx context.Context) (*Status, bool) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make([]error, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			done := make(chan error, 1)
+			go func() { done <- check(ctx) }()
+			select {
+			case results[i] = <-done:
+			case <-ctx.Done():
+				results[i] = errors.New("Timed Out")
+			}
+		}(i, check.check)
+	}
+	wg.Wait()
+
+	status := &Status{Status: "ready", Checks: make(map[string]string)}
+	ready := true
+	for i, check := range c.checks {
+		status.Checks[check.name] = "ok"
+		if results[i] != nil {
+			status.Checks[check.name] = results[i].Error()
+			status.Status = "unavailable"
+			ready = false
+		}
+	}
+	return status, ready
+}
+
+// Answers 200 as long as the process can serve requests at all (liveness).
+func LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	respond(w, http.StatusOK, &Status{Status: "ok"})
+}
+
+// Answers 200 if all checks pass, 503 with the failed checks otherwise (readiness).
+func (c *Checker) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	status, ready := c.Run(r.Context())
+	if !ready {
+		respond(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	respond(w, http.StatusOK, status)
+}
+
+func respond(w http.ResponseWriter, code int, status *Status) {
+	response, _ := json.Marshal(status)
+	w.Header().Set("content-type", "application/json")
+	w.Header().Set("cache-control", "no-store")
+	w.WriteHeader(code)
+	w.Write(response)
+}
+
+// Requests url (e.g. http://localhost:8080/readyz), returns an error unless it answers 200.
+// Used by the healthcheck subcommand, so containers can be checked without curl or wget.
+func Probe(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+	res, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return errors.New("Not Ready: " + res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadiness(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	dbErr := errors.New("Could Not Reach DB...")
+	var failing error
+	checker.AddCheck("db", func(ctx context.Context) error { return failing })
+	checker.AddCheck("schema", func(ctx context.Context) error { return nil })
+
+	server := httptest.NewServer(http.HandlerFunc(checker.ReadinessHandler))
+	defer server.Close()
+	require.NoError(t, Probe(server.URL, time.Second))
+
+	failing = dbErr
+	res := httptest.NewRecorder()
+	checker.ReadinessHandler(res, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	var status Status
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &status))
+	assert.Equal(t, "unavailable", status.Status)
+	assert.Equal(t, map[string]string{"db": dbErr.Error(), "schema": "ok"}, status.Checks)
+	assert.Error(t, Probe(server.URL, time.Second))
+}
+
+func TestReadinessTimeout(t *testing.T) {
+	checker := NewChecker(10 * time.Millisecond)
+	block := make(chan struct{})
+	defer close(block)
+	checker.AddCheck("db", func(ctx context.Context) error { <-block; return nil })
+
+	status, ready := checker.Run(context.Background())
+	assert.False(t, ready, "Expected A Hanging Check To Fail")
+	assert.Equal(t, "Timed Out", status.Checks["db"])
+}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrInvalidFormat = errors.New("Invalid Log Format (expected text or json)")
+
+type Config struct {
+	Level  string // e.g. debug, info, warn, error (see logrus.ParseLevel)
+	Format string // text or json
+	File   string // also written to stdout, empty for stdout only
+	// Rotation, File is rotated once it reaches MaxSize bytes and MaxBackups rotated files are kept (0 to never rotate).
+	MaxSize    int64
+	MaxBackups int
+}
+
+// Creates a logger writing to stdout (and cfg.File), returns a Closer for the file (nil if there is none).
+func NewLogger(cfg Config) (*log.Logger, io.Closer, error) {
+	logger := log.New()
+	level, err := log.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Level = level
+
+	switch strings.ToLower(cfg.Format) {
+	case "", "text":
+		logger.SetFormatter(&log.TextFormatter{
+			DisableColors: false,
+			ForceColors:   true,
+			FullTimestamp: false,
+		})
+	case "json":
+		logger.SetFormatter(&log.JSONFormatter{})
+	default:
+		return nil, nil, ErrInvalidFormat
+	}
+
+	if cfg.File == "" {
+		logger.Out = os.Stdout
+		return logger, nil, nil
+	}
+	file, err := OpenRotatingFile(cfg.File, cfg.MaxSize, cfg.MaxBackups)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Out = io.MultiWriter(os.Stdout, file)
+	return logger, file, nil
+}
+
+type fieldsKey struct{}
+
+// Adds fields (e.g. story_id) to those logged for ctx.
+func WithFields(ctx context.Context, fields log.Fields) context.Context {
+	merged := make(log.Fields)
+	for key, value := range Fields(ctx) {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// Fields logged for ctx (e.g. its request_id), nil if there are none.
+func Fields(ctx context.Context) log.Fields {
+	fields, _ := ctx.Value(fieldsKey{}).(log.Fields)
+	return fields
+}
+
+// Logs with the fields of ctx (those of its request).
+func FromContext(ctx context.Context, logger *log.Logger) *log.Entry {
+	return logger.WithFields(Fields(ctx))
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	var out bytes.Buffer
+	logger := log.New()
+	logger.Out = &out
+	logger.SetFormatter(&log.JSONFormatter{})
+
+	ctx := WithFields(context.Background(), log.Fields{"request_id": "abc", "route": "/add"})
+	storyCtx := WithFields(ctx, log.Fields{"story_id": 7})
+	FromContext(storyCtx, logger).Error("Could Not Update Story Title.")
+
+	line := out.String()
+	assert.Contains(t, line, `"request_id":"abc"`)
+	assert.Contains(t, line, `"story_id":7`)
+	assert.NotContains(t, Fields(ctx), "story_id", "Expected Parent Context To Keep Its Fields")
+
+	out.Reset()
+	FromContext(context.Background(), logger).Info("Started")
+	assert.NotContains(t, out.String(), "request_id")
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "logs.txt")
+
+	file, err := OpenRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+
+	read := func(path string) string {
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		return string(content)
+	}
+	assert.Equal(t, "fourth\n", read(path))
+	assert.Equal(t, "third\n", read(path+".1"))
+	assert.Equal(t, "second\n", read(path+".2"))
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err), "Expected Only MaxBackups Files To Be Kept")
+}
+
+func TestNewLoggerConfig(t *testing.T) {
+	_, _, err := NewLogger(Config{Level: "verbose"})
+	require.Error(t, err)
+	_, _, err = NewLogger(Config{Level: "info", Format: "xml"})
+	require.Equal(t, ErrInvalidFormat, err)
+
+	logger, closer, err := NewLogger(Config{Level: "warn", Format: "JSON"})
+	require.NoError(t, err)
+	assert.Nil(t, closer)
+	assert.Equal(t, log.WarnLevel, logger.Level)
+	assert.IsType(t, &log.JSONFormatter{}, logger.Formatter)
+}
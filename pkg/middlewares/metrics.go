@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Keeps the status a handler responded with.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Counts requests and their latency by route (template, e.g. "/stories/{story}"), method and status.
+// Used with router.Use, so the route is known.
+func RequestMetrics(reg prometheus.Registerer) mux.MiddlewareFunc {
+	labels := []string{"route", "method", "status"}
+	factory := promauto.With(reg)
+	requests := factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "collab_http_requests_total",
+		Help: "HTTP requests handled.",
+	}, labels)
+	latency := factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "collab_http_request_duration_seconds",
+		Help:    "Time taken to handle HTTP requests.",
+		Buckets: prometheus.DefBuckets,
+	}, labels)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			startTime := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			route := routeTemplate(r)
+			status := strconv.Itoa(recorder.status)
+			requests.WithLabelValues(route, r.Method, status).Inc()
+			latency.WithLabelValues(route, r.Method, status).Observe(time.Since(startTime).Seconds())
+		})
+	}
+}
+
+// Template of the route r matched (e.g. "/stories/{story}"), so IDs don't make every path distinct.
+func routeTemplate(r *http.Request) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		if template, err := current.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unmatched"
+}
@@ -1,19 +1,37 @@
 package middlewares
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
 	"github.com/sirupsen/logrus"
 )
 
+// Logs how long a request took (request counts and latencies are exposed by RequestMetrics).
 func DurationLogger(next http.HandlerFunc, logger *logrus.Logger) http.HandlerFunc {
-	total := 0 // Implement better metric logic later.
 	return func(w http.ResponseWriter, r *http.Request) {
 		startTime := time.Now()
 		next(w, r)
-		logger.Info("Request at \"", r.URL.Path, "\" Processed in ", time.Now().Sub(startTime))
-		total++
-		logger.Info("Total Requests: ", total)
+		logging.FromContext(r.Context(), logger).WithField("duration", time.Now().Sub(startTime).String()).
+			Info("Request at \"", r.URL.Path, "\" Processed")
+	}
+}
+
+// Only lets requests with "Authorization: Bearer <token>" through.
+// If token is empty, admin endpoints are disabled altogether.
+func AdminOnly(next http.HandlerFunc, token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if token == "" || !strings.HasPrefix(auth, "Bearer ") ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) != 1 {
+			w.Header().Set("content-type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"admin access required"}`))
+			return
+		}
+		next(w, r)
 	}
 }
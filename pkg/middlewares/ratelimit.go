@@ -0,0 +1,311 @@
+package middlewares
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// Header a client identifies its contributor with (limited on top of its IP).
+const ContributorHeader = "X-Contributor"
+
+var ErrInvalidRateLimit = errors.New("Invalid Rate Limit")
+
+// A token bucket: up to Burst requests at once, refilled at Burst per Period (a zero RateLimit does not limit).
+type RateLimit struct {
+	Burst  int
+	Period time.Duration
+}
+
+func (l RateLimit) enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+// Tokens added to the bucket per second.
+func (l RateLimit) rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// Limits of a route, per client IP and per contributor.
+type RouteLimits struct {
+	IP          RateLimit
+	Contributor RateLimit
+}
+
+// Parses a rate limit like "20/1m" (20 requests per minute, all at once at most), "off" disables limiting.
+func ParseRateLimit(value string) (RateLimit, error) {
+	if value == "off" || value == "0" {
+		return RateLimit{}, nil
+	}
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+	burst, err := strconv.Atoi(parts[0])
+	if err != nil || burst < 0 {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+	period, err := time.ParseDuration(parts[1])
+	if err != nil || period <= 0 {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+	return RateLimit{Burst: burst, Period: period}, nil
+}
+
+// Parses route limits like "ip=60/1m,contributor=20/1m" (either may be left out, "off" disables both).
+func ParseRouteLimits(value string) (RouteLimits, error) {
+	var limits RouteLimits
+	if value == "off" {
+		return limits, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return limits, ErrInvalidRateLimit
+		}
+		limit, err := ParseRateLimit(kv[1])
+		if err != nil {
+			return limits, err
+		}
+		switch kv[0] {
+		case "ip":
+			limits.IP = limit
+		case "contributor":
+			limits.Contributor = limit
+		default:
+			return limits, ErrInvalidRateLimit
+		}
+	}
+	return limits, nil
+}
+
+// A token bucket, by key (e.g. "add:ip:1.2.3.4").
+type Bucket struct {
+	Key   string
+	Limit RateLimit
+}
+
+// Where token buckets are kept, in memory (MemoryRateLimitStore) or shared between instances (e.g. in Redis).
+type RateLimitStore interface {
+	// Takes cost tokens from every one of buckets, only if they all have enough. Otherwise nothing is taken,
+	// and it returns false and how long until they all have enough again.
+	// A cost above a Burst only needs a full bucket, and is paid off before the bucket refills.
+	Take(buckets []Bucket, cost int, now time.Time) (bool, time.Duration, error)
+}
+
+// Limits requests per route, by client IP and by contributor (X-Contributor header).
+type RateLimiter struct {
+	store          RateLimitStore
+	trustedProxies []*net.IPNet
+	logger         *logrus.Logger
+}
+
+// X-Forwarded-For and X-Real-IP headers are only trusted from trustedProxies (e.g. a load balancer).
+func NewRateLimiter(store RateLimitStore, trustedProxies []*net.IPNet, logger *logrus.Logger) *RateLimiter {
+	limiter := new(RateLimiter)
+	limiter.store = store
+	limiter.trustedProxies = trustedProxies
+	limiter.logger = logger
+	return limiter
+}
+
+// Parses a comma separated list of IPs and CIDRs (e.g. "10.0.0.0/8,127.0.0.1").
+func ParseTrustedProxies(value string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, proxy := range strings.Split(value, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, ipNet)
+	}
+	return proxies, nil
+}
+
+// Responds with 429 (and Retry-After) once a client IP or contributor runs out of requests for route.
+func (l *RateLimiter) Limit(next http.HandlerFunc, route string, limits RouteLimits) http.HandlerFunc {
+	return l.LimitCost(next, route, limits, nil)
+}
+
+// Same as Limit, for requests that cost more than one token (e.g. a batch costs a token per Word).
+// A nil cost costs 1, and so does a cost below 1 (no request is free).
+// The IP and contributor buckets are only charged if both have enough tokens, so a request rejected
+// for its contributor does not use up the IP's tokens (shared by everyone behind it).
+func (l *RateLimiter) LimitCost(next http.HandlerFunc, route string, limits RouteLimits, cost func(r *http.Request) int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var buckets []Bucket
+		if limits.IP.enabled() {
+			buckets = append(buckets, Bucket{Key: route + ":ip:" + l.ClientIP(r), Limit: limits.IP})
+		}
+		if contributor := r.Header.Get(ContributorHeader); contributor != "" && limits.Contributor.enabled() {
+			buckets = append(buckets, Bucket{Key: route + ":contributor:" + contributor, Limit: limits.Contributor})
+		}
+		if len(buckets) == 0 {
+			next(w, r)
+			return
+		}
+
+		tokens := 1
+		if cost != nil {
+			tokens = cost(r)
+		}
+		if tokens < 1 {
+			tokens = 1
+		}
+		if !l.take(w, r, buckets, tokens) {
+			return
+		}
+		next(w, r)
+	}
+}
+
+// Takes tokens from buckets, responds with 429 and returns false if there are too few.
+func (l *RateLimiter) take(w http.ResponseWriter, r *http.Request, buckets []Bucket, cost int) bool {
+	allowed, retryAfter, err := l.store.Take(buckets, cost, time.Now())
+	if err != nil {
+		// A broken store should not take the service down with it.
+		logging.FromContext(r.Context(), l.logger).WithError(err).Error("Could Not Check Rate Limit")
+		return true
+	}
+	if allowed {
+		return true
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write([]byte(`{"error":"rate limit exceeded"}`))
+	return false
+}
+
+// IP of the client, as forwarded by trusted proxies (the first untrusted address from the right of X-Forwarded-For).
+func (l *RateLimiter) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !l.trusted(host) {
+		return host
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if !l.trusted(hop) {
+				return hop
+			}
+		}
+		return strings.TrimSpace(hops[0])
+	}
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return strings.TrimSpace(realIP)
+	}
+	return host
+}
+
+func (l *RateLimiter) trusted(host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range l.trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// How often full buckets are swept from memory.
+const rateLimitSweepInterval = time.Minute
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	limit  RateLimit
+}
+
+// In-Memory RateLimitStore (buckets are not shared between instances).
+type MemoryRateLimitStore struct {
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	sync.Mutex
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	s := new(MemoryRateLimitStore)
+	s.buckets = make(map[string]*tokenBucket)
+	s.lastSweep = time.Now()
+	return s
+}
+
+func (s *MemoryRateLimitStore) Take(buckets []Bucket, cost int, now time.Time) (bool, time.Duration, error) {
+	defer s.Unlock()
+	s.Lock()
+
+	if now.Sub(s.lastSweep) > rateLimitSweepInterval {
+		s.sweep(now)
+	}
+
+	allowed := true
+	var wait float64 // seconds until every bucket has enough
+	refilled := make([]*tokenBucket, len(buckets))
+	for i, b := range buckets {
+		bucket := s.refill(b, now)
+		refilled[i] = bucket
+		needed := math.Min(float64(cost), float64(b.Limit.Burst))
+		if bucket.tokens < needed {
+			allowed = false
+			wait = math.Max(wait, (needed-bucket.tokens)/b.Limit.rate())
+		}
+	}
+	if !allowed {
+		return false, time.Duration(wait * float64(time.Second)), nil
+	}
+	for _, bucket := range refilled {
+		bucket.tokens -= float64(cost) // may go below 0, paid off before the next request
+	}
+	return true, 0, nil
+}
+
+// Key's bucket with the tokens added since it was last used. Caller must hold the lock.
+func (s *MemoryRateLimitStore) refill(b Bucket, now time.Time) *tokenBucket {
+	bucket, ok := s.buckets[b.Key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(b.Limit.Burst), last: now, limit: b.Limit}
+		s.buckets[b.Key] = bucket
+	}
+	bucket.tokens = math.Min(float64(b.Limit.Burst), bucket.tokens+now.Sub(bucket.last).Seconds()*b.Limit.rate())
+	bucket.last = now
+	bucket.limit = b.Limit
+	return bucket
+}
+
+// Drops buckets that have refilled, they are the same as new ones. Caller must hold the lock.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	for key, bucket := range s.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*bucket.limit.rate() >= float64(bucket.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
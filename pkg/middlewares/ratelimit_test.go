@@ -0,0 +1,132 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	limit := RateLimit{Burst: 2, Period: 2 * time.Second} // a token per second
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		allowed, _, err := store.Take([]Bucket{{Key: "key", Limit: limit}}, 1, now)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+	allowed, retryAfter, err := store.Take([]Bucket{{Key: "key", Limit: limit}}, 1, now)
+	require.NoError(t, err)
+	assert.False(t, allowed, "Expected Burst To Be Used Up")
+	assert.Equal(t, time.Second, retryAfter)
+
+	allowed, _, _ = store.Take([]Bucket{{Key: "other", Limit: limit}}, 1, now)
+	assert.True(t, allowed, "Expected Keys To Have Their Own Buckets")
+
+	allowed, _, _ = store.Take([]Bucket{{Key: "key", Limit: limit}}, 1, now.Add(time.Second))
+	assert.True(t, allowed, "Expected Bucket To Refill")
+}
+
+func TestMemoryRateLimitStoreCost(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	limit := RateLimit{Burst: 2, Period: 2 * time.Second} // a token per second
+	now := time.Now()
+
+	allowed, _, _ := store.Take([]Bucket{{Key: "key", Limit: limit}}, 5, now)
+	assert.True(t, allowed, "Expected A Cost Above The Burst To Need A Full Bucket")
+	allowed, retryAfter, _ := store.Take([]Bucket{{Key: "key", Limit: limit}}, 1, now.Add(time.Second))
+	assert.False(t, allowed, "Expected Cost To Be Paid Off First")
+	assert.Equal(t, 3*time.Second, retryAfter)
+	allowed, _, _ = store.Take([]Bucket{{Key: "key", Limit: limit}}, 1, now.Add(4*time.Second))
+	assert.True(t, allowed)
+}
+
+func TestParseRouteLimits(t *testing.T) {
+	limits, err := ParseRouteLimits("ip=60/1m, contributor=20/30s")
+	require.NoError(t, err)
+	assert.Equal(t, RateLimit{Burst: 60, Period: time.Minute}, limits.IP)
+	assert.Equal(t, RateLimit{Burst: 20, Period: 30 * time.Second}, limits.Contributor)
+
+	limits, err = ParseRouteLimits("off")
+	require.NoError(t, err)
+	assert.False(t, limits.IP.enabled())
+
+	for _, invalid := range []string{"ip=60", "ip=x/1m", "ip=1/0s", "user=1/1m"} {
+		_, err = ParseRouteLimits(invalid)
+		assert.Equal(t, ErrInvalidRateLimit, err, invalid)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8, 127.0.0.1")
+	require.NoError(t, err)
+	limiter := NewRateLimiter(NewMemoryRateLimitStore(), proxies, logrus.New())
+	handler := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}, "add", RouteLimits{IP: RateLimit{Burst: 2, Period: time.Minute}, Contributor: RateLimit{Burst: 1, Period: time.Minute}})
+
+	request := func(remoteAddr string, forwardedFor string, contributor string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/add", nil)
+		r.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			r.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		if contributor != "" {
+			r.Header.Set(ContributorHeader, contributor)
+		}
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, request("1.2.3.4:1000", "", "alice").Code)
+	w := request("1.2.3.4:1000", "", "alice")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "Expected Contributor To Be Limited")
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusCreated, request("1.2.3.4:1000", "", "").Code, "Expected Rejected Request Not To Take IP Tokens")
+	assert.Equal(t, http.StatusTooManyRequests, request("1.2.3.4:1000", "", "").Code, "Expected IP To Be Limited")
+
+	// Behind a trusted proxy, the forwarded client IP is limited.
+	assert.Equal(t, http.StatusCreated, request("10.0.0.1:1000", "5.6.7.8, 10.0.0.2", "").Code)
+	assert.Equal(t, http.StatusCreated, request("127.0.0.1:1000", "5.6.7.8", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1:1000", "5.6.7.8", "").Code)
+
+	// A request rejected for its contributor leaves the IP's tokens to others behind it.
+	assert.Equal(t, http.StatusCreated, request("2.3.4.5:1000", "", "bob").Code)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusTooManyRequests, request("2.3.4.5:1000", "", "bob").Code)
+	}
+	assert.Equal(t, http.StatusCreated, request("2.3.4.5:1000", "", "carol").Code, "Expected IP Tokens Not To Be Taken")
+
+	// An untrusted client cannot spoof its IP.
+	assert.Equal(t, http.StatusTooManyRequests, request("1.2.3.4:1000", "9.9.9.9", "").Code)
+}
+
+func TestRateLimiterCost(t *testing.T) {
+	limiter := NewRateLimiter(NewMemoryRateLimitStore(), nil, logrus.New())
+	cost := 3
+	handler := limiter.LimitCost(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}, "add", RouteLimits{IP: RateLimit{Burst: 5, Period: time.Minute}}, func(r *http.Request) int { return cost })
+
+	request := func() int {
+		r := httptest.NewRequest("POST", "/add/batch", nil)
+		r.RemoteAddr = "1.2.3.4:1000"
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusCreated, request())
+	assert.Equal(t, http.StatusTooManyRequests, request(), "Expected Only 2 Tokens To Be Left")
+	cost = 2
+	assert.Equal(t, http.StatusCreated, request())
+	cost = 0
+	assert.Equal(t, http.StatusTooManyRequests, request(), "Expected A Cost Below 1 To Cost 1")
+}
@@ -0,0 +1,62 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// Request IDs sent by clients (or a proxy) are kept if they are at most this long.
+const MaxRequestIDLength = 64
+
+// Gives each request an ID (the X-Request-ID it was sent with, or a new one), echoed in the response.
+// Everything logged for the request has request_id, route, and story_id and contributor when known.
+// Used with router.Use, so the route is known.
+func RequestID() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestId := r.Header.Get(RequestIDHeader)
+			if !validRequestID(requestId) {
+				requestId = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestId)
+
+			fields := log.Fields{"request_id": requestId, "route": routeTemplate(r)}
+			if storyId := mux.Vars(r)["story"]; storyId != "" {
+				fields["story_id"] = storyId
+			}
+			if contributor := r.Header.Get(ContributorHeader); contributor != "" {
+				fields["contributor"] = contributor
+			}
+			if span := trace.SpanContextFromContext(r.Context()); span.IsValid() {
+				fields["trace_id"] = span.TraceID().String()
+			}
+			next.ServeHTTP(w, r.WithContext(logging.WithFields(r.Context(), fields)))
+		})
+	}
+}
+
+func validRequestID(requestId string) bool {
+	if requestId == "" || len(requestId) > MaxRequestIDLength {
+		return false
+	}
+	for _, c := range requestId {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	var fields logrus.Fields
+	router := mux.NewRouter()
+	router.Use(RequestID())
+	router.HandleFunc("/stories/{story}", func(w http.ResponseWriter, r *http.Request) {
+		fields = logging.Fields(r.Context())
+	})
+
+	req := httptest.NewRequest("GET", "/stories/12", nil)
+	req.Header.Set(RequestIDHeader, "client-id.1")
+	req.Header.Set(ContributorHeader, "alice")
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	assert.Equal(t, "client-id.1", res.Header().Get(RequestIDHeader))
+	assert.Equal(t, "client-id.1", fields["request_id"])
+	assert.Equal(t, "/stories/{story}", fields["route"])
+	assert.Equal(t, "12", fields["story_id"])
+	assert.Equal(t, "alice", fields["contributor"])
+
+	req = httptest.NewRequest("GET", "/stories/12", nil)
+	req.Header.Set(RequestIDHeader, "not a valid id\n")
+	res = httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	assert.Len(t, res.Header().Get(RequestIDHeader), 32, "Expected Invalid IDs To Be Replaced")
+	assert.NotContains(t, fields, "contributor")
+}
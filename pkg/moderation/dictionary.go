@@ -0,0 +1,103 @@
+package moderation
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/sentence"
+)
+
+// Plural suffixes of a language, and what replaces them in the singular ("stories" to "story").
+var pluralSuffixes = map[string][][2]string{
+	"en": {{"ies", "y"}, {"ves", "f"}, {"ves", "fe"}, {"es", ""}, {"s", ""}},
+	"es": {{"ces", "z"}, {"es", ""}, {"s", ""}},
+	"fr": {{"aux", "al"}, {"x", ""}, {"s", ""}},
+	"de": {{"en", ""}, {"er", ""}, {"n", ""}, {"e", ""}, {"s", ""}},
+}
+
+// A list of real Words of a language, loaded from a word list file (see LoadWordList).
+type Dictionary struct {
+	language     string
+	path         string
+	words        map[string]bool
+	sync.RWMutex // guards words
+}
+
+func NewDictionary(language string, path string) (*Dictionary, error) {
+	dict := new(Dictionary)
+	dict.language = language
+	dict.path = path
+	if err := dict.Reload(); err != nil {
+		return nil, err
+	}
+	return dict, nil
+}
+
+// Reads the word list file again (the old list is kept if it cannot be read).
+func (d *Dictionary) Reload() error {
+	list, err := LoadWordList(d.path)
+	if err != nil {
+		return err
+	}
+	words := wordSet(list)
+
+	d.Lock()
+	defer d.Unlock()
+	d.words = words
+	return nil
+}
+
+func (d *Dictionary) Language() string {
+	return d.language
+}
+
+// Number of Words in the Dictionary.
+func (d *Dictionary) Len() int {
+	d.RLock()
+	defer d.RUnlock()
+	return len(d.words)
+}
+
+// True if word (ignoring case and trailing punctuation), or its singular, is in the Dictionary.
+func (d *Dictionary) Contains(word string) bool {
+	word = matchKey(word)
+
+	d.RLock()
+	defer d.RUnlock()
+	if d.words[word] {
+		return true
+	}
+	for _, suffix := range pluralSuffixes[d.language] {
+		if stem := strings.TrimSuffix(word, suffix[0]); stem != word && stem != "" && d.words[stem+suffix[1]] {
+			return true
+		}
+	}
+	return false
+}
+
+// Only accepts real Words, found in any of the Dictionaries (one per language).
+type Dictionaries []*Dictionary
+
+func (ds Dictionaries) FilterWord(word string) error {
+	if len(ds) == 0 || sentence.IsPunctuation(word) {
+		return nil
+	}
+	for _, dict := range ds {
+		if dict.Contains(word) {
+			return nil
+		}
+	}
+	return &RejectedError{Filter: "dictionary", Reason: "Word Not In Dictionary"}
+}
+
+// Reloads all Dictionaries, returns the number of Words in each (by language).
+func (ds Dictionaries) Reload() (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, dict := range ds {
+		if err := dict.Reload(); err != nil {
+			return nil, err
+		}
+		counts[dict.Language()] = dict.Len()
+	}
+	return counts, nil
+}
@@ -0,0 +1,60 @@
+package moderation
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeWordList(t *testing.T, dir string, content string) string {
+	path := filepath.Join(dir, "words.txt")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestDictionary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dictionary")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	dict, err := NewDictionary("en", writeWordList(t, dir, "story\nbox\nwolf\nCat\n"))
+	require.NoError(t, err)
+
+	for _, word := range []string{"story", "Stories", "boxes", "wolves", "cat", "CATS.", "cat!"} {
+		assert.True(t, dict.Contains(word), "Expected %q In Dictionary", word)
+	}
+	for _, word := range []string{"dog", "s", "catt"} {
+		assert.False(t, dict.Contains(word), "Expected %q Not In Dictionary", word)
+	}
+
+	writeWordList(t, dir, "dog\n")
+	counts, err := Dictionaries{dict}.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"en": 1}, counts)
+	assert.True(t, dict.Contains("dogs"))
+	assert.False(t, dict.Contains("cat"))
+}
+
+func TestDictionariesFilter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dictionary")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	dict, err := NewDictionary("en", writeWordList(t, dir, "once\nupon\n"))
+	require.NoError(t, err)
+	filter := Chain{NewBlocklist([]string{"darn"}), Dictionaries{dict}}
+
+	assert.NoError(t, filter.FilterWord("Once"))
+	assert.NoError(t, filter.FilterWord("!"))
+	assert.NoError(t, Dictionaries(nil).FilterWord("dragon"), "Expected No Dictionaries To Allow Any Word")
+
+	err = filter.FilterWord("dragon")
+	var rejected *RejectedError
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, "dictionary", rejected.Filter)
+}
@@ -0,0 +1,151 @@
+package moderation
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Characters commonly substituted for letters (leetspeak).
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "2", "z", "3", "e", "4", "a",
+	"5", "s", "6", "g", "7", "t", "8", "b", "9", "g",
+	"@", "a", "$", "s", "|", "l", "+", "t", "!", "i",
+)
+
+// Lowercases a Word, undoes leetspeak and drops anything that is not a letter ("F.U.C.K" to "fuck").
+func normalizeForMatch(word string) string {
+	word = strings.ToLower(sentence.TrimPunctuation(word))
+	word = leetReplacer.Replace(word)
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+		return -1
+	}, word)
+}
+
+// Splits a Word into its letters and how many times each repeats in a row ("hello" to "helo", [1 1 2 1]).
+func letterRuns(word string) (string, []int) {
+	var b strings.Builder
+	var runs []int
+	var last rune
+	for i, r := range word {
+		if i > 0 && r == last {
+			runs[len(runs)-1]++
+			continue
+		}
+		b.WriteRune(r)
+		runs = append(runs, 1)
+		last = r
+	}
+	return b.String(), runs
+}
+
+// Does a Word's letter runs match a blocked Word's ? Runs of 3 or more are stretched spellings ("fuuuuck"),
+// any shorter run must match exactly so "bob" is not "boob" and "heel" is not "hell".
+func runsMatch(runs []int, blocked []int) bool {
+	for i := range runs {
+		if runs[i] != blocked[i] && (runs[i] < 3 || runs[i] < blocked[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Rejects blocked Words, including leetspeak and stretched spellings of them.
+type Blocklist struct {
+	words map[string][][]int // letter runs of blocked Words, by their letters
+}
+
+func NewBlocklist(words []string) *Blocklist {
+	b := new(Blocklist)
+	b.words = make(map[string][][]int)
+	for _, word := range words {
+		normalized := normalizeForMatch(word)
+		if normalized == "" {
+			continue
+		}
+		letters, runs := letterRuns(normalized)
+		b.words[letters] = append(b.words[letters], runs)
+	}
+	return b
+}
+
+func (b *Blocklist) FilterWord(word string) error {
+	normalized := normalizeForMatch(word)
+	if normalized == "" {
+		return nil // punctuation only
+	}
+	letters, runs := letterRuns(normalized)
+	for _, blocked := range b.words[letters] {
+		if runsMatch(runs, blocked) {
+			return &RejectedError{Filter: "blocklist", Reason: "Word Is Not Allowed"}
+		}
+	}
+	return nil
+}
+
+// Lowercases a Word and drops its trailing punctuation, to look it up in a word list ("Once," to "once").
+func matchKey(word string) string {
+	return strings.ToLower(sentence.TrimPunctuation(norm.NFC.String(word)))
+}
+
+// Set of the match keys of words.
+func wordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[matchKey(word)] = true
+	}
+	return set
+}
+
+// Only accepts Words in the list (case insensitive), for deployments that want a fixed vocabulary.
+type Allowlist struct {
+	words map[string]bool
+}
+
+func NewAllowlist(words []string) *Allowlist {
+	a := new(Allowlist)
+	a.words = wordSet(words)
+	return a
+}
+
+func (a *Allowlist) FilterWord(word string) error {
+	if sentence.IsPunctuation(word) {
+		return nil
+	}
+	if !a.words[matchKey(word)] {
+		return &RejectedError{Filter: "allowlist", Reason: "Word Is Not In The Allowed Word List"}
+	}
+	return nil
+}
+
+// Requires a Story's Title to mention its theme (one of its Prompt's keywords), once the Title is complete.
+type ThemeFilter struct{}
+
+func NewThemeFilter() *ThemeFilter {
+	return new(ThemeFilter)
+}
+
+func (f *ThemeFilter) FilterWord(word string) error {
+	return nil // needs context
+}
+
+func (f *ThemeFilter) FilterWordInContext(word string, ctx WordContext) error {
+	if len(ctx.Keywords) == 0 || ctx.TitleWords != 1 || sentence.IsPunctuation(word) {
+		return nil // no theme, or not the last Word of the Title
+	}
+	for _, w := range strings.Fields(ctx.Title + " " + word) {
+		w = normalizeForMatch(w)
+		for _, keyword := range ctx.Keywords {
+			// Keyword may be part of a Word ("pirate" in "pirates").
+			if keyword = normalizeForMatch(keyword); keyword != "" && strings.Contains(w, keyword) {
+				return nil
+			}
+		}
+	}
+	return &RejectedError{Filter: "theme", Reason: "Title Must Mention The Theme: " + strings.Join(ctx.Keywords, ", ")}
+}
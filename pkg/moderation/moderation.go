@@ -0,0 +1,146 @@
+package moderation
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strings"
+	"time"
+)
+
+// Code sent to clients (in WordError) when a Word is rejected by a WordFilter.
+const RejectedCode = "word_rejected"
+
+// Decides whether a Word may be added to a Story. Custom filters implement this (or use WordFilterFunc).
+type WordFilter interface {
+	// Returns a *RejectedError if word is not allowed, nil otherwise.
+	FilterWord(word string) error
+}
+
+// Adapts a plain function to a WordFilter.
+type WordFilterFunc func(word string) error
+
+func (f WordFilterFunc) FilterWord(word string) error {
+	return f(word)
+}
+
+// Where a Word is about to be added.
+type WordContext struct {
+	Room       string
+	Prompt     string
+	Keywords   []string // of the Prompt's theme
+	Title      string   // Title so far
+	TitleWords int32    // Words the Title still needs (0 if Word goes to a Sentence)
+}
+
+// A WordFilter that also checks Words against the Story they are added to.
+// FilterWordInContext is called (under the Room's lock) only after FilterWord accepted the Word.
+type ContextFilter interface {
+	WordFilter
+	FilterWordInContext(word string, ctx WordContext) error
+}
+
+// Runs WordFilters in order, stopping at the first rejection.
+type Chain []WordFilter
+
+func (c Chain) FilterWord(word string) error {
+	for _, filter := range c {
+		if err := filter.FilterWord(word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Runs the ContextFilters of the Chain in order, stopping at the first rejection.
+func (c Chain) FilterWordInContext(word string, ctx WordContext) error {
+	for _, filter := range c {
+		if cf, ok := filter.(ContextFilter); ok {
+			if err := cf.FilterWordInContext(word, ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type RejectedError struct {
+	Filter string // Which filter rejected the Word (e.g. "blocklist").
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return "Word Rejected: " + e.Reason
+}
+
+// A rejected Word, recorded for moderators.
+type RejectedWord struct {
+	ID        int32     `json:"id"`
+	Room      string    `json:"room"`
+	Word      string    `json:"word"`
+	Filter    string    `json:"filter"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RejectedWordsResponse struct {
+	Limit   int32          `json:"limit"`
+	Offset  int32          `json:"offset"`
+	Results []RejectedWord `json:"results"`
+}
+
+type RejectionStorage interface {
+	AddRejectedWord(ctx context.Context, rejected RejectedWord) error
+	GetRejectedWords(ctx context.Context, limit int32, offset int32) ([]RejectedWord, error)
+}
+
+// Reads a word list file (one word per line, blank lines and lines starting with # are skipped).
+func LoadWordList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, scanner.Err()
+}
+
+// Moderator actions recorded in the audit trail.
+const (
+	ActionReplaceWord    = "replace_word"
+	ActionDeleteWord     = "delete_word"
+	ActionRedactSentence = "redact_sentence"
+	ActionRenameStory    = "rename_story"
+)
+
+// A change made by a moderator.
+type AuditEntry struct {
+	ID         int32     `json:"id"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	StoryID    int32     `json:"story_id"`
+	SentenceID int32     `json:"sentence_id,omitempty"` // 0 for Story Title changes
+	Before     string    `json:"before"`
+	After      string    `json:"after"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AuditEntriesResponse struct {
+	Limit   int32        `json:"limit"`
+	Offset  int32        `json:"offset"`
+	Results []AuditEntry `json:"results"`
+}
+
+type AuditStorage interface {
+	AddAuditEntry(ctx context.Context, entry AuditEntry) error
+	GetAuditEntries(ctx context.Context, limit int32, offset int32) ([]AuditEntry, error)
+}
@@ -0,0 +1,98 @@
+package moderation
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocklist(t *testing.T) {
+	blocklist := NewBlocklist([]string{"darn", "ass"})
+
+	for _, word := range []string{"darn", "DARN", "darn!", "d4rn", "D@RN", "daaarn", "d.a.r.n", "ass", "a$$"} {
+		err := blocklist.FilterWord(word)
+		var rejected *RejectedError
+		require.True(t, errors.As(err, &rejected), "Expected %q To Be Blocked", word)
+		assert.Equal(t, "blocklist", rejected.Filter)
+	}
+
+	for _, word := range []string{"darning", "as", "class", "hello", "!"} {
+		assert.NoError(t, blocklist.FilterWord(word), "Expected %q To Be Allowed", word)
+	}
+}
+
+func TestBlocklistDoubledLetters(t *testing.T) {
+	blocklist := NewBlocklist([]string{"boob", "hell"})
+
+	for _, word := range []string{"boob", "booooob", "hell", "hellll", "heeell"} {
+		assert.Error(t, blocklist.FilterWord(word), "Expected %q To Be Blocked", word)
+	}
+	for _, word := range []string{"bob", "heel", "hel", "heell"} {
+		assert.NoError(t, blocklist.FilterWord(word), "Expected %q To Be Allowed", word)
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	allowlist := NewAllowlist([]string{"once", "upon", "a", "time"})
+
+	assert.NoError(t, allowlist.FilterWord("Once"))
+	assert.NoError(t, allowlist.FilterWord("time."))
+	assert.NoError(t, allowlist.FilterWord(","))
+	assert.Error(t, allowlist.FilterWord("dragon"))
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	custom := WordFilterFunc(func(word string) error {
+		calls = append(calls, word)
+		if word == "custom" {
+			return &RejectedError{Filter: "custom", Reason: "Custom Rejection"}
+		}
+		return nil
+	})
+	chain := Chain{NewBlocklist([]string{"darn"}), custom}
+
+	assert.Error(t, chain.FilterWord("darn"))
+	assert.Empty(t, calls, "Expected Chain To Stop At First Rejection")
+
+	assert.Error(t, chain.FilterWord("custom"))
+	assert.NoError(t, chain.FilterWord("fine"))
+	assert.Equal(t, []string{"custom", "fine"}, calls)
+}
+
+func TestLoadWordList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wordlist")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "words.txt")
+	require.NoError(t, ioutil.WriteFile(path, []byte("# comment\nfirst\n\n  second  \n"), 0644))
+
+	words, err := LoadWordList(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, words)
+}
+
+func TestThemeFilter(t *testing.T) {
+	filter := Chain{NewBlocklist([]string{"darn"}), NewThemeFilter()}
+	ctx := WordContext{Room: "default", Prompt: "space pirates", Keywords: []string{"pirate", "star"}, Title: "The", TitleWords: 1}
+
+	assert.NoError(t, filter.FilterWord("anything"), "Expected Theme To Need Context")
+	assert.NoError(t, filter.FilterWordInContext("Pirates!", ctx))
+	assert.NoError(t, filter.FilterWordInContext("Starship", ctx))
+
+	err := filter.FilterWordInContext("Cat", ctx)
+	var rejected *RejectedError
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, "theme", rejected.Filter)
+
+	assert.NoError(t, filter.FilterWordInContext(",", ctx), "Expected Punctuation Not To Complete The Title")
+	assert.NoError(t, filter.FilterWordInContext("Cat", WordContext{Keywords: ctx.Keywords, Title: "Pirate", TitleWords: 1}))
+	assert.NoError(t, filter.FilterWordInContext("Cat", WordContext{Keywords: ctx.Keywords, TitleWords: 2}), "Expected Only The Last Title Word To Be Checked")
+	assert.NoError(t, filter.FilterWordInContext("Cat", WordContext{TitleWords: 1}), "Expected No Theme To Allow Any Title")
+}
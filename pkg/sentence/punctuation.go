@@ -0,0 +1,65 @@
+package sentence
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Punctuation allowed at the end of a Word (and as a Word of its own).
+const Punctuation = ".,!?;:"
+
+var (
+	repeatedPunctuation = regexp.MustCompile(`([,!?;:])[,!?;:]*`)
+	repeatedDots        = regexp.MustCompile(`\.{2,}`)
+)
+
+// Normalizes a Word to NFC and collapses repeated trailing punctuation ("end!!!" to "end!", "wait...." to "wait...").
+func NormalizeWord(word string) string {
+	word = norm.NFC.String(word)
+	core := TrimPunctuation(word)
+	punctuation := word[len(core):]
+	punctuation = repeatedDots.ReplaceAllStringFunc(punctuation, func(dots string) string {
+		if len(dots) == 2 {
+			return "."
+		}
+		return "..."
+	})
+	punctuation = repeatedPunctuation.ReplaceAllString(punctuation, "$1")
+	return core + punctuation
+}
+
+// Word without its trailing punctuation.
+func TrimPunctuation(word string) string {
+	return strings.TrimRight(word, Punctuation)
+}
+
+// True for a Word made only of punctuation (e.g. "," or "!"), which attaches to the previous Word.
+func IsPunctuation(word string) bool {
+	return len(word) > 0 && TrimPunctuation(word) == ""
+}
+
+// True if Word ends with ".", "!" or "?" (an ellipsis does not end a Sentence).
+func EndsSentence(word string) bool {
+	if strings.HasSuffix(word, "...") {
+		return false
+	}
+	return strings.HasSuffix(word, ".") || strings.HasSuffix(word, "!") || strings.HasSuffix(word, "?")
+}
+
+// Appends a Word to content, punctuation is attached to the previous Word without a space.
+func AppendWord(content string, word string) string {
+	if content == "" {
+		return word
+	}
+	if IsPunctuation(word) {
+		return content + word
+	}
+	return content + " " + word
+}
+
+// Number of Words in content (punctuation attached to Words is not counted separately).
+func CountWords(content string) int32 {
+	return int32(len(strings.Fields(content)))
+}
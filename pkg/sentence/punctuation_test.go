@@ -0,0 +1,34 @@
+package sentence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeWord(t *testing.T) {
+	assert.Equal(t, "end!", NormalizeWord("end!!!"))
+	assert.Equal(t, "wait...", NormalizeWord("wait....."))
+	assert.Equal(t, "wait.", NormalizeWord("wait.."))
+	assert.Equal(t, "what?", NormalizeWord("what???"))
+	assert.Equal(t, "U.S.A.", NormalizeWord("U.S.A."))
+	assert.Equal(t, ",", NormalizeWord(",,"))
+}
+
+func TestAppendWord(t *testing.T) {
+	content := AppendWord("", "Once")
+	content = AppendWord(content, "upon")
+	content = AppendWord(content, ",")
+	content = AppendWord(content, "time.")
+	assert.Equal(t, "Once upon, time.", content)
+	assert.Equal(t, int32(3), CountWords(content))
+}
+
+func TestEndsSentence(t *testing.T) {
+	assert.True(t, EndsSentence("end."))
+	assert.True(t, EndsSentence("really?"))
+	assert.True(t, EndsSentence("!"))
+	assert.False(t, EndsSentence("wait..."))
+	assert.False(t, EndsSentence("however,"))
+	assert.False(t, EndsSentence("word"))
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+)
+
+type StoryStateRequest struct {
+	State str.State `json:"state"`
+}
+
+// Get All Stories (including hidden ones) from Storage
+func (s *Server) GetAdminStoriesHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStoryFilter(r, s.pageSize)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(filter.States) == 0 {
+		filter.States = str.AllStates
+	}
+	s.respondWithStories(w, r, filter)
+}
+
+// Moves a Story to another State (e.g. hide an offensive Story, archive an old one).
+func (s *Server) UpdateStoryStateHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["story"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var stateReq StoryStateRequest
+	if err := json.Unmarshal(body, &stateReq); err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	story, err := s.wordService.CloseStory(r.Context(), int32(id), stateReq.State)
+	s.respondWithStory(w, story, err)
+}
+
+// Finishes a Story right away (including its partially written Paragraph and Sentence).
+func (s *Server) FinishStoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["story"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	story, err := s.wordService.CloseStory(r.Context(), int32(id), str.StateFinished)
+	s.respondWithStory(w, story, err)
+}
+
+// Responds with a (changed) Story, or with the status matching err.
+func (s *Server) respondWithStory(w http.ResponseWriter, story *str.Story, err error) {
+	switch err {
+	case nil:
+		s.RespondWithJSON(w, http.StatusOK, *story)
+	case str.ErrInvalidState:
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+	case str.ErrStoryNotFound:
+		s.RespondWithError(w, http.StatusNotFound, err.Error())
+	case str.ErrInvalidTransition:
+		s.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+)
+
+// ETag of a Story, changes whenever Story's content version changes.
+func storyETag(story *str.Story) string {
+	return fmt.Sprintf("\"s%d-v%d\"", story.ID, story.Version)
+}
+
+// ETag of a page of Stories, changes whenever any listed Story (or total count) changes.
+func storiesETag(storiesRes *str.StoriesResponse) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%d:%d", storiesRes.Limit, storiesRes.Offset, storiesRes.Count)
+	for _, story := range storiesRes.Results {
+		fmt.Fprintf(h, ";%d-%d", story.ID, story.Version)
+	}
+	return fmt.Sprintf("\"l%x\"", h.Sum64())
+}
+
+// Latest UpdatedAt among a page of Stories.
+func storiesLastModified(storiesRes *str.StoriesResponse) time.Time {
+	var lastModified time.Time
+	for _, story := range storiesRes.Results {
+		if story.UpdatedAt.After(lastModified) {
+			lastModified = story.UpdatedAt
+		}
+	}
+	return lastModified
+}
+
+// Sets ETag and Last-Modified headers, and responds with 304 if request's
+// If-None-Match (or If-Modified-Since) shows the client already has this version.
+// Returns true if 304 was sent.
+func (s *Server) RespondIfNotModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	// If-None-Match takes precedence over If-Modified-Since (RFC 7232).
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		// Header has only second precision.
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotModified(t *testing.T) {
+	updatedAt := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	etag := storyETag(&str.Story{ID: 1, Version: 3})
+
+	r := httptest.NewRequest("GET", "/stories/1", nil)
+	assert.False(t, notModified(r, etag, updatedAt), "Expected Unconditional Request To Be Modified")
+
+	r.Header.Set("If-None-Match", "\"s1-v2\", "+etag)
+	assert.True(t, notModified(r, etag, updatedAt), "Expected Matching ETag To Be Not Modified")
+
+	r.Header.Set("If-None-Match", "W/"+etag)
+	assert.True(t, notModified(r, etag, updatedAt), "Expected Weak Matching ETag To Be Not Modified")
+
+	r.Header.Set("If-None-Match", "\"s1-v2\"")
+	r.Header.Set("If-Modified-Since", updatedAt.Format(http.TimeFormat))
+	assert.False(t, notModified(r, etag, updatedAt), "Expected If-None-Match To Take Precedence")
+
+	r.Header.Del("If-None-Match")
+	assert.True(t, notModified(r, etag, updatedAt), "Expected Unchanged Story To Be Not Modified")
+
+	r.Header.Set("If-Modified-Since", updatedAt.Add(-time.Second).Format(http.TimeFormat))
+	assert.False(t, notModified(r, etag, updatedAt), "Expected Updated Story To Be Modified")
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+)
+
+// Forks a Story from a Sentence, into a new Story in its own Room.
+func (s *Server) ForkStoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["story"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var forkReq wrd.ForkRequest
+	if err := json.Unmarshal(body, &forkReq); err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	story, err := s.wordService.ForkStory(r.Context(), int32(id), forkReq)
+	switch err {
+	case nil:
+		s.RespondWithJSON(w, http.StatusCreated, *story)
+	case wrd.ErrInvalidForkPosition, str.ErrInvalidRoomName:
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+	case str.ErrStoryNotFound:
+		s.RespondWithError(w, http.StatusNotFound, err.Error())
+	case wrd.ErrRoomBusy:
+		s.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// Get Stories forked from a Story.
+func (s *Server) GetForksHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["story"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	forks, err := s.storyService.GetForks(r.Context(), int32(id))
+	if err == str.ErrStoryNotFound {
+		s.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.RespondWithJSON(w, http.StatusOK, forks)
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
+	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+)
+
+// Get Words rejected by moderation (latest first) for moderators.
+func (s *Server) GetRejectedWordsHandler(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 32)
+	if err != nil {
+		limit = 10 // default value
+	}
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 32)
+	if err != nil {
+		offset = 0 // default value
+	}
+
+	rejectedWords, err := s.wordService.GetRejectedWords(r.Context(), int32(limit), int32(offset))
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.RespondWithJSON(w, http.StatusOK, moderation.RejectedWordsResponse{
+		Limit:   int32(limit),
+		Offset:  int32(offset),
+		Results: rejectedWords,
+	})
+}
+
+// Reloads dictionary word lists from their files (without restarting).
+func (s *Server) ReloadDictionariesHandler(w http.ResponseWriter, r *http.Request) {
+	counts, err := s.dictionaries.Reload()
+	if err != nil {
+		s.logger.WithError(err).Error("Could Not Reload Dictionaries.")
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.RespondWithJSON(w, http.StatusOK, map[string]map[string]int{"words": counts})
+}
+
+type SentenceWordRequest struct {
+	Word string `json:"word"`
+}
+
+type StoryTitleRequest struct {
+	Title string `json:"title"`
+}
+
+// Moderator making a change (for the audit trail), from the X-Moderator header.
+func moderatorFromRequest(r *http.Request) string {
+	if moderator := r.Header.Get("X-Moderator"); moderator != "" {
+		return moderator
+	}
+	return "admin"
+}
+
+// Get all Sentences (with IDs) of a Story, to find the one to edit.
+func (s *Server) GetStorySentencesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["story"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	sentences, err := s.wordService.GetStorySentences(r.Context(), int32(id))
+	if err == str.ErrStoryNotFound {
+		s.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.RespondWithJSON(w, http.StatusOK, sentences)
+}
+
+// Replaces a Word (at {index}) of a Sentence.
+func (s *Server) ReplaceWordHandler(w http.ResponseWriter, r *http.Request) {
+	id, index, ok := s.sentenceWordFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var wordReq SentenceWordRequest
+	if err := json.Unmarshal(body, &wordReq); err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sentence, err := s.wordService.ReplaceWord(r.Context(), id, index, wordReq.Word, moderatorFromRequest(r))
+	s.respondWithEdit(w, sentence, err)
+}
+
+// Deletes a Word (at {index}) of a Sentence.
+func (s *Server) DeleteWordHandler(w http.ResponseWriter, r *http.Request) {
+	id, index, ok := s.sentenceWordFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	sentence, err := s.wordService.DeleteWord(r.Context(), id, index, moderatorFromRequest(r))
+	s.respondWithEdit(w, sentence, err)
+}
+
+// Redacts a whole Sentence.
+func (s *Server) RedactSentenceHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["sentence"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	sentence, err := s.wordService.RedactSentence(r.Context(), int32(id), moderatorFromRequest(r))
+	s.respondWithEdit(w, sentence, err)
+}
+
+// Renames a Story (replaces its Title).
+func (s *Server) RenameStoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["story"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var titleReq StoryTitleRequest
+	if err := json.Unmarshal(body, &titleReq); err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	story, err := s.wordService.RenameStory(r.Context(), int32(id), titleReq.Title, moderatorFromRequest(r))
+	s.respondWithEdit(w, story, err)
+}
+
+// Get moderators' changes (latest first).
+func (s *Server) GetAuditEntriesHandler(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 32)
+	if err != nil {
+		limit = 10 // default value
+	}
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 32)
+	if err != nil {
+		offset = 0 // default value
+	}
+
+	entries, err := s.wordService.GetAuditEntries(r.Context(), int32(limit), int32(offset))
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.RespondWithJSON(w, http.StatusOK, moderation.AuditEntriesResponse{
+		Limit:   int32(limit),
+		Offset:  int32(offset),
+		Results: entries,
+	})
+}
+
+// Reads {sentence} and {index} from route, responds with an error (and returns false) if they are invalid.
+func (s *Server) sentenceWordFromRequest(w http.ResponseWriter, r *http.Request) (int32, int, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["sentence"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return 0, 0, false
+	}
+	index, err := strconv.Atoi(vars["index"])
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, wrd.ErrInvalidWordIndex.Error())
+		return 0, 0, false
+	}
+	return int32(id), index, true
+}
+
+// Responds with an edited Sentence or Story, or with the status matching err.
+func (s *Server) respondWithEdit(w http.ResponseWriter, edited interface{}, err error) {
+	switch err {
+	case nil:
+		s.RespondWithJSON(w, http.StatusOK, edited)
+	case wrd.ErrSentenceNotFound, str.ErrStoryNotFound:
+		s.RespondWithError(w, http.StatusNotFound, err.Error())
+	default:
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+)
+
+// Get a Room (and its StoryRules)
+func (s *Server) GetRoomHandler(w http.ResponseWriter, r *http.Request) {
+	room, err := s.storyService.GetRoom(r.Context(), mux.Vars(r)["room"])
+	if err == str.ErrInvalidRoomName {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.RespondWithJSON(w, http.StatusOK, *room)
+}
+
+// Creates or updates a Room's StoryRules
+func (s *Server) UpdateRoomHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var rules str.StoryRules
+	if err := json.Unmarshal(body, &rules); err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	room := str.Room{Name: mux.Vars(r)["room"], Rules: rules}
+	switch err := s.storyService.SaveRoom(r.Context(), room); err {
+	case nil:
+		s.RespondWithJSON(w, http.StatusOK, room)
+	case str.ErrInvalidRoomName, str.ErrInvalidRules:
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+	default:
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// Chooses the prompt (theme) a Room's next Story is seeded with, an empty prompt clears it.
+func (s *Server) SetRoomPromptHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var prompt str.Prompt
+	if err := json.Unmarshal(body, &prompt); err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	saved, err := s.wordService.SetRoomPrompt(r.Context(), mux.Vars(r)["room"], prompt)
+	switch err {
+	case nil:
+		s.RespondWithJSON(w, http.StatusOK, saved)
+	case str.ErrInvalidRoomName, str.ErrInvalidPrompt:
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+	default:
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
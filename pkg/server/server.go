@@ -1,12 +1,18 @@
 package server
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
 	str "github.com/shubhamdwivedii/collab-story/pkg/story"
 	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
 	log "github.com/sirupsen/logrus"
@@ -15,18 +21,57 @@ import (
 type Server struct {
 	wordService  *wrd.WordService
 	storyService *str.StoryService
+	dictionaries moderation.Dictionaries // reloaded by ReloadDictionariesHandler
+	clientIP     func(r *http.Request) string
+	pageSize     int32 // Stories listed when a request has no limit
 	logger       *log.Logger
 }
 
+const DefaultPageSize = 10
+
 func NewServer(wordService *wrd.WordService, storyService *str.StoryService, logger *log.Logger) (*Server, error) {
 	sv := new(Server)
 	sv.wordService = wordService
 	sv.storyService = storyService
+	sv.pageSize = DefaultPageSize
+	sv.clientIP = remoteIP
 	sv.logger = logger
 	return sv, nil
 }
 
-// Adds a Word to Story/Paragraph/Sentence in Storage.
+// Dictionaries (also in the WordService's moderation filter) reloaded by ReloadDictionariesHandler.
+func (s *Server) SetDictionaries(dictionaries moderation.Dictionaries) {
+	s.dictionaries = dictionaries
+}
+
+// Sets how the IP of a request's client is found (e.g. RateLimiter.ClientIP, behind trusted proxies).
+func (s *Server) SetClientIP(clientIP func(r *http.Request) string) {
+	s.clientIP = clientIP
+}
+
+// Sets how many Stories are listed when a request has no limit.
+func (s *Server) SetPageSize(size int32) {
+	s.pageSize = size
+}
+
+// IP the request came from (ignoring proxies).
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Room a Word is added to, DefaultRoom unless route has a {room}.
+func roomFromRequest(r *http.Request) string {
+	if room, ok := mux.Vars(r)["room"]; ok {
+		return room
+	}
+	return str.DefaultRoom
+}
+
+// Adds a Word to (Room's) Story/Paragraph/Sentence in Storage.
 func (s *Server) AddWordHandler(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 
@@ -49,40 +94,208 @@ func (s *Server) AddWordHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	wrdRes, err := s.wordService.AddWord(wrdReq.Word) // Also Verifies Word
+	var wrdRes *wrd.WordResponse
+	opts := wrd.WordOptions{
+		SlotToken:        r.Header.Get("Slot-Token"), // from POST /slots, if the contributor reserved the next position
+		ExpectedPosition: wrdReq.ExpectedPosition,
+	}
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		var replayed bool
+		wrdRes, replayed, err = s.wordService.AddWordWithKey(r.Context(), roomFromRequest(r), key, wrd.HashRequest(body), wrdReq.Word, opts) // Also Verifies Word
+		if err == wrd.ErrIdempotencyKeyReused {
+			s.RespondWithJSON(w, http.StatusUnprocessableEntity, wrd.WordError{Error: err.Error()})
+			return
+		}
+		if replayed {
+			w.Header().Set("Idempotent-Replayed", "true")
+		}
+	} else {
+		wrdRes, err = s.wordService.AddWordWithOptions(r.Context(), roomFromRequest(r), wrdReq.Word, opts) // Also Verifies Word
+	}
+
 	if err != nil {
-		s.logger.Error("AddWord Failed:" + err.Error())
+		logging.FromContext(r.Context(), s.logger).WithError(err).Error("AddWord Failed")
 		// s.RespondWithError(w, http.StatusBadRequest, err.Error())
-		wrdErr := wrd.WordError{Error: err.Error()}
-		s.RespondWithJSON(w, http.StatusBadRequest, wrdErr)
+		s.respondWithWordError(w, err)
 		return
 	}
 
+	if wrdRes.Candidate != nil {
+		// Word is only a candidate, it is added if it wins the vote.
+		s.RespondWithJSON(w, http.StatusAccepted, *wrdRes)
+		return
+	}
 	s.RespondWithJSON(w, http.StatusCreated, *wrdRes)
 }
 
+// Number of Words in a batch request (at least 1), to rate limit batches per Word. Leaves the body to be read again.
+func BatchCost(r *http.Request) int {
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 1
+	}
+	var batchReq wrd.WordBatchRequest
+	if err := json.Unmarshal(body, &batchReq); err != nil || len(batchReq.Words) == 0 || len(batchReq.Words) > wrd.MaxBatchSize {
+		return 1 // rejected by AddWordsHandler
+	}
+	return len(batchReq.Words)
+}
+
+// Adds a batch of Words (in order) to Stories/Paragraphs/Sentences in Storage.
+func (s *Server) AddWordsHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	contentType := r.Header.Get("content-type")
+	if contentType != "application/json" {
+		s.RespondWithError(w, http.StatusUnsupportedMediaType, "content type 'application/json' required")
+		return
+	}
+
+	var batchReq wrd.WordBatchRequest
+	err = json.Unmarshal(body, &batchReq)
+
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := s.wordService.AddWords(r.Context(), roomFromRequest(r), batchReq.Words) // Also Verifies Words
+	if err != nil {
+		logging.FromContext(r.Context(), s.logger).WithError(err).Error("AddWords Failed")
+		if len(results) == 0 {
+			s.respondWithWordError(w, err)
+			return
+		}
+		// Some Words were added before the failure, report them too.
+		batchRes := wrd.WordBatchResponse{Results: results, Error: err.Error()}
+		s.RespondWithJSON(w, http.StatusBadRequest, batchRes)
+		return
+	}
+
+	s.RespondWithJSON(w, http.StatusCreated, wrd.WordBatchResponse{Results: results})
+}
+
+// Undoes the last Word of a Room, sent with the Undo-Token header of the Word's response.
+func (s *Server) UndoWordHandler(w http.ResponseWriter, r *http.Request) {
+	wordId, err := strconv.ParseInt(mux.Vars(r)["word"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	err = s.wordService.UndoWord(r.Context(), roomFromRequest(r), int32(wordId), r.Header.Get("Undo-Token"))
+	if err != nil && s.respondWithSlotError(w, err) {
+		return
+	}
+	switch err {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case wrd.ErrUndoNotFound:
+		s.RespondWithJSON(w, http.StatusNotFound, wrd.WordError{Error: err.Error()})
+	case wrd.ErrUndoToken:
+		s.RespondWithJSON(w, http.StatusForbidden, wrd.WordError{Error: err.Error()})
+	case wrd.ErrUndoExpired, wrd.ErrUndoConflict:
+		s.RespondWithJSON(w, http.StatusConflict, wrd.WordError{Error: err.Error()})
+	case str.ErrInvalidRoomName:
+		s.RespondWithJSON(w, http.StatusBadRequest, wrd.WordError{Error: err.Error()})
+	default:
+		s.RespondWithJSON(w, http.StatusInternalServerError, wrd.WordError{Error: err.Error()})
+	}
+}
+
+// Responds with a WordError, rejected (moderated) Words, Slot errors and position mismatches get their own status (and code).
+func (s *Server) respondWithWordError(w http.ResponseWriter, err error) {
+	if s.respondWithSlotError(w, err) {
+		return
+	}
+	wrdErr := wrd.WordError{Error: err.Error()}
+	var mismatch *wrd.PositionMismatchError
+	if errors.As(err, &mismatch) {
+		wrdErr.Code = wrd.PositionMismatchCode
+		wrdErr.Current = &mismatch.Current
+		s.RespondWithJSON(w, http.StatusConflict, wrdErr)
+		return
+	}
+	var rejected *moderation.RejectedError
+	if errors.As(err, &rejected) {
+		wrdErr.Code = moderation.RejectedCode
+		s.RespondWithJSON(w, http.StatusUnprocessableEntity, wrdErr)
+		return
+	}
+	s.RespondWithJSON(w, http.StatusBadRequest, wrdErr)
+}
+
 // Get All Stories from Storage
 func (s *Server) GetStoriesHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStoryFilter(r, s.pageSize)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, state := range filter.States {
+		if !state.Visible() {
+			s.RespondWithError(w, http.StatusBadRequest, str.ErrInvalidState.Error())
+			return
+		}
+	}
+
+	s.respondWithStories(w, r, filter)
+}
+
+func (s *Server) respondWithStories(w http.ResponseWriter, r *http.Request, filter str.StoryFilter) {
+	storiesRes, err := s.storyService.GetAllStories(r.Context(), filter)
+
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if s.RespondIfNotModified(w, r, storiesETag(storiesRes), storiesLastModified(storiesRes)) {
+		return
+	}
+	s.RespondWithJSON(w, http.StatusAccepted, *storiesRes)
+}
+
+// Reads limit (pageSize by default), offset, room and (comma separated) state from query.
+func parseStoryFilter(r *http.Request, pageSize int32) (str.StoryFilter, error) {
 	limitQry := r.URL.Query().Get("limit")
 	offsetQry := r.URL.Query().Get("offset")
+	stateQry := r.URL.Query().Get("state")
+	roomQry := r.URL.Query().Get("room")
 
 	limit, err := strconv.ParseInt(limitQry, 10, 32)
 	if err != nil {
-		limit = 10 // default value
+		limit = int64(pageSize) // default value
 	}
 	offset, err := strconv.ParseInt(offsetQry, 10, 32)
 	if err != nil {
 		offset = 0 // default value
 	}
 
-	storiesRes, err := s.storyService.GetAllStories(int32(limit), int32(offset))
-
-	if err != nil {
-		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
-		return
+	filter := str.StoryFilter{Limit: int32(limit), Offset: int32(offset)}
+	if roomQry != "" {
+		if err := str.ValidateRoomName(roomQry); err != nil {
+			return filter, err
+		}
+		filter.Room = roomQry
 	}
-	s.RespondWithJSON(w, http.StatusAccepted, *storiesRes)
-	return
+	if stateQry != "" {
+		for _, state := range strings.Split(stateQry, ",") {
+			state := str.State(strings.TrimSpace(state))
+			if !state.Valid() {
+				return filter, str.ErrInvalidState
+			}
+			filter.States = append(filter.States, state)
+		}
+	}
+	return filter, nil
 }
 
 // Get Story by ID from Storage
@@ -93,9 +306,29 @@ func (s *Server) GetStoryHandler(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	// Check Story's version first, so an unchanged Story skips loading all Paragraphs.
+	story, err := s.storyService.GetStory(r.Context(), int32(id))
+	if err != nil || !story.State.Visible() {
+		s.RespondWithError(w, http.StatusNotFound, str.ErrStoryNotFound.Error())
+		return
+	}
+	if s.RespondIfNotModified(w, r, storyETag(story), story.UpdatedAt) {
+		return
+	}
+
+	storyRes, err := s.storyService.GetStoryDetail(r.Context(), int32(id))
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 
-	storyRes, err := s.storyService.GetStoryDetail(int32(id))
+	// Story may have changed since the check, send the version that was actually loaded.
+	story.Version = storyRes.Version
+	w.Header().Set("ETag", storyETag(story))
+	w.Header().Set("Last-Modified", storyRes.UpdatedAt.UTC().Format(http.TimeFormat))
 	s.RespondWithJSON(w, http.StatusCreated, *storyRes)
 }
 
@@ -106,7 +339,7 @@ func (s *Server) RespondWithError(w http.ResponseWriter, code int, msg string) {
 func (s *Server) RespondWithJSON(w http.ResponseWriter, code int, data interface{}) {
 	response, err := json.Marshal(data)
 	if err != nil {
-		s.logger.Error("Error Marshalling Response Data", err)
+		s.logger.WithError(err).Error("Error Marshalling Response Data")
 	}
 	w.Header().Add("content-type", "application/json")
 	w.WriteHeader(code)
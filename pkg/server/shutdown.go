@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Serves httpServer on listener (over TLS if it has a TLSConfig) until ctx is done (e.g. on SIGTERM), then stops accepting connections
+// and waits up to drainTimeout for in-flight requests to complete.
+// Returns an error if serving failed, or if requests were still running after drainTimeout.
+func Serve(ctx context.Context, httpServer *http.Server, listener net.Listener, drainTimeout time.Duration) error {
+	served := make(chan error, 1)
+	go func() {
+		if httpServer.TLSConfig != nil {
+			served <- httpServer.ServeTLS(listener, "", "") // certificates are in TLSConfig
+			return
+		}
+		served <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		return err // never http.ErrServerClosed, as Shutdown was not called
+	case <-ctx.Done():
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(drainCtx); err != nil {
+		httpServer.Close() // drop requests that did not finish in time
+		return err
+	}
+	return nil
+}
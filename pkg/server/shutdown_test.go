@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/shubhamdwivedii/collab-story/pkg/paragraph"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A Story being written (Title "Once", Sentence 3 "upon"), holding UpdateSentence until released like a
+// slow transaction. Only what adding a Word to the Sentence needs is implemented.
+type slowStorage struct {
+	wrd.WordStorage
+	sentence Sentence
+	updating chan struct{}
+	release  chan struct{}
+	sync.Mutex
+}
+
+func (s *slowStorage) GetRoom(ctx context.Context, name string) (*str.Room, error) {
+	return nil, str.ErrRoomNotFound
+}
+
+func (s *slowStorage) GetUnfinishedStory(ctx context.Context, room string) (*str.Story, error) {
+	return &str.Story{ID: 1, Room: room, Title: "Once", TitleAdded: true, State: str.StateWriting}, nil
+}
+
+func (s *slowStorage) GetUnfinishedParagraph(ctx context.Context, storyId int32) (*Paragraph, error) {
+	return &Paragraph{ID: 2, Story: storyId}, nil
+}
+
+func (s *slowStorage) GetUnfinishedSentence(ctx context.Context, paragraphId int32) (*Sentence, error) {
+	return s.GetSentence(ctx, 3)
+}
+
+func (s *slowStorage) GetSentence(ctx context.Context, sentenceId int32) (*Sentence, error) {
+	defer s.Unlock()
+	s.Lock()
+	sentence := s.sentence
+	return &sentence, nil
+}
+
+func (s *slowStorage) UpdateSentence(ctx context.Context, sentenceId int32, word string, rules str.StoryRules) (*wrd.SentenceUpdate, error) {
+	close(s.updating)
+	<-s.release
+	defer s.Unlock()
+	s.Lock()
+	s.sentence.Content += " " + word
+	return &wrd.SentenceUpdate{Sentence: s.sentence}, nil
+}
+
+func TestServeDrainsInFlightAddWord(t *testing.T) {
+	storage := &slowStorage{
+		sentence: Sentence{ID: 3, Paragraph: 2, Content: "upon"},
+		updating: make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+	server, err := NewServer(wrd.NewWordService(storage, log.New()), nil, log.New())
+	require.NoError(t, err)
+	httpServer := &http.Server{Handler: http.HandlerFunc(server.AddWordHandler)}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	url := "http://" + listener.Addr().String() + "/add"
+
+	ctx, shutdown := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, httpServer, listener, 5*time.Second)
+	}()
+
+	responded := make(chan *http.Response, 1)
+	go func() {
+		res, err := http.Post(url, "application/json", strings.NewReader(`{"word":"a"}`))
+		if err != nil {
+			t.Error(err)
+			close(responded)
+			return
+		}
+		responded <- res
+	}()
+
+	<-storage.updating // AddWord is mid-write when the signal arrives
+	shutdown()
+	select {
+	case <-served:
+		t.Fatal("Expected Serve To Wait For The In-Flight Request")
+	case <-time.After(50 * time.Millisecond):
+	}
+	_, err = http.Post(url, "application/json", strings.NewReader(`{"word":"b"}`))
+	assert.Error(t, err, "Expected New Connections To Be Refused While Draining")
+
+	close(storage.release)
+	res := <-responded
+	require.NotNil(t, res)
+	res.Body.Close()
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.NoError(t, <-served)
+	sentence, err := storage.GetSentence(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, "upon a", sentence.Content)
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	mw "github.com/shubhamdwivedii/collab-story/pkg/middlewares"
+	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+)
+
+// Reserves the next position of a Room for a few seconds, the returned token is sent as Slot-Token header with POST /add.
+func (s *Server) ReserveSlotHandler(w http.ResponseWriter, r *http.Request) {
+	clients := []string{"ip:" + s.clientIP(r)}
+	if contributor := r.Header.Get(mw.ContributorHeader); contributor != "" {
+		clients = append(clients, "contributor:"+contributor)
+	}
+	slot, err := s.wordService.ReserveSlot(r.Context(), roomFromRequest(r), clients...)
+	if err != nil {
+		s.respondWithSlotError(w, err)
+		return
+	}
+	s.RespondWithJSON(w, http.StatusCreated, *slot)
+}
+
+// Responds to Slot errors, returns false (without responding) for any other error.
+func (s *Server) respondWithSlotError(w http.ResponseWriter, err error) bool {
+	var reserved *wrd.SlotReservedError
+	var cooldown *wrd.SlotCooldownError
+	switch {
+	case errors.As(err, &reserved):
+		// Position is free again once the Slot expires.
+		retryAfter := math.Ceil(time.Until(reserved.ExpiresAt).Seconds())
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Max(retryAfter, 1))))
+		s.RespondWithJSON(w, http.StatusConflict, wrd.WordError{Error: err.Error()})
+	case errors.As(err, &cooldown):
+		retryAfter := math.Ceil(time.Until(cooldown.Until).Seconds())
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Max(retryAfter, 1))))
+		s.RespondWithJSON(w, http.StatusTooManyRequests, wrd.WordError{Error: err.Error()})
+	case err == wrd.ErrSlotNotFound, err == wrd.ErrSlotConflict:
+		s.RespondWithJSON(w, http.StatusConflict, wrd.WordError{Error: err.Error()})
+	case err == wrd.ErrSlotsDisabled:
+		s.RespondWithJSON(w, http.StatusNotImplemented, wrd.WordError{Error: err.Error()})
+	case err == wrd.ErrVotingSlot, err == str.ErrInvalidRoomName:
+		s.RespondWithJSON(w, http.StatusBadRequest, wrd.WordError{Error: err.Error()})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	str "github.com/shubhamdwivedii/collab-story/pkg/story"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+)
+
+// Get the open voting round (candidates and their votes) of a Room.
+func (s *Server) GetOpenRoundHandler(w http.ResponseWriter, r *http.Request) {
+	round, err := s.wordService.GetOpenRound(r.Context(), roomFromRequest(r))
+	s.respondWithRound(w, round, err)
+}
+
+// Get a voting round (candidates, votes and the winner once closed).
+func (s *Server) GetRoundHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["round"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	round, err := s.wordService.GetRound(r.Context(), int32(id))
+	s.respondWithRound(w, round, err)
+}
+
+// Votes for a candidate Word of an open voting round.
+func (s *Server) VoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["round"], 10, 32)
+	if err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, "Invalid Id")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var voteReq wrd.VoteRequest
+	if err := json.Unmarshal(body, &voteReq); err != nil {
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	round, err := s.wordService.Vote(r.Context(), int32(id), voteReq)
+	s.respondWithRound(w, round, err)
+}
+
+// Responds with a voting round, or with the status matching err.
+func (s *Server) respondWithRound(w http.ResponseWriter, round *wrd.VoteRound, err error) {
+	switch err {
+	case nil:
+		s.RespondWithJSON(w, http.StatusOK, *round)
+	case wrd.ErrInvalidVoter, str.ErrInvalidRoomName:
+		s.RespondWithError(w, http.StatusBadRequest, err.Error())
+	case wrd.ErrRoundNotFound, wrd.ErrCandidateNotFound:
+		s.RespondWithError(w, http.StatusNotFound, err.Error())
+	case wrd.ErrRoundClosed, wrd.ErrAlreadyVoted:
+		s.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		s.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+)
+
+// How often expired Idempotency Keys are swept from memory.
+const sweepInterval = time.Minute
+
+// An Idempotency Key is only unique within its Room.
+type roomKey struct {
+	room string
+	key  string
+}
+
+// In-Memory Idempotency Key Storage (keys are lost on restart, and not shared between instances).
+type IdempotencyStorage struct {
+	entries   map[roomKey]wrd.IdempotentResponse
+	since     time.Time // Oldest time still inside idempotency window (as last seen).
+	lastSweep time.Time
+	sync.Mutex
+}
+
+func NewIdempotencyStorage() *IdempotencyStorage {
+	s := new(IdempotencyStorage)
+	s.entries = make(map[roomKey]wrd.IdempotentResponse)
+	s.lastSweep = time.Now()
+	return s
+}
+
+// Gets a stored Response for a Room's Idempotency Key (only if stored after since).
+func (s *IdempotencyStorage) GetIdempotentResponse(ctx context.Context, room string, key string, since time.Time) (*wrd.IdempotentResponse, error) {
+	defer s.Unlock()
+	s.Lock()
+
+	s.since = since
+	idemRes, ok := s.entries[roomKey{room, key}]
+	if !ok {
+		return nil, wrd.ErrIdempotencyKeyNotFound
+	}
+	if idemRes.CreatedAt.Before(since) {
+		delete(s.entries, roomKey{room, key})
+		return nil, wrd.ErrIdempotencyKeyNotFound
+	}
+	return &idemRes, nil
+}
+
+// Stores a Response for a Room's Idempotency Key (replacing an expired one, if any).
+func (s *IdempotencyStorage) SaveIdempotentResponse(ctx context.Context, idemRes wrd.IdempotentResponse) error {
+	defer s.Unlock()
+	s.Lock()
+
+	s.entries[roomKey{idemRes.Room, idemRes.Key}] = idemRes
+
+	if time.Since(s.lastSweep) > sweepInterval {
+		for key, entry := range s.entries {
+			if entry.CreatedAt.Before(s.since) {
+				delete(s.entries, key)
+			}
+		}
+		s.lastSweep = time.Now()
+	}
+	return nil
+}
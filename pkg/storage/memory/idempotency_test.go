@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStorage(t *testing.T) {
+	storage := NewIdempotencyStorage()
+	now := time.Now()
+
+	_, err := storage.GetIdempotentResponse(context.Background(), "room", "key", now.Add(-time.Hour))
+	require.Equal(t, wrd.ErrIdempotencyKeyNotFound, err)
+
+	err = storage.SaveIdempotentResponse(context.Background(), wrd.IdempotentResponse{
+		Room:        "room",
+		Key:         "key",
+		RequestHash: wrd.HashRequest([]byte(`{"word":"word"}`)),
+		Response:    wrd.WordResponse{ID: 1, Title: "word"},
+		CreatedAt:   now,
+	})
+	require.NoError(t, err)
+
+	idemRes, err := storage.GetIdempotentResponse(context.Background(), "room", "key", now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "word", idemRes.Response.Title, "Expected Stored Response To Match")
+
+	// Keys are only unique within a Room.
+	_, err = storage.GetIdempotentResponse(context.Background(), "other", "key", now.Add(-time.Hour))
+	require.Equal(t, wrd.ErrIdempotencyKeyNotFound, err, "Expected Key Not To Be Found In Another Room")
+
+	// Key stored before window should have expired.
+	_, err = storage.GetIdempotentResponse(context.Background(), "room", "key", now.Add(time.Second))
+	require.Equal(t, wrd.ErrIdempotencyKeyNotFound, err)
+}
@@ -0,0 +1,195 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/paragraph"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+)
+
+// Gets all Sentences (with IDs, in order) of a Story from DB
+func (s *MySQLStorage) GetStorySentences(ctx context.Context, storyId int32) ([]Sentence, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetStorySentences")
+	defer span.End()
+
+	query := sq.Select("s.id", "s.paragraph", "s.isFinished", "s.content").From("sentences s").
+		Join("paragraphs p ON p.id = s.paragraph").
+		Where(sq.Eq{"p.story": storyId}).OrderBy("s.id")
+
+	rows, err := query.RunWith(s.runner(ctx)).Query()
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Getting Story Sentences From DB")
+		return nil, errors.New("Error Getting Story Sentences From DB...")
+	}
+	defer rows.Close()
+
+	sentences := []Sentence{}
+	for rows.Next() {
+		var sentence Sentence
+		var isFinished int32
+		if err := rows.Scan(
+			&sentence.ID,
+			&sentence.Paragraph,
+			&isFinished,
+			&sentence.Content,
+		); err != nil {
+			logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Sentence Row")
+			return nil, errors.New("Error Getting Story Sentences From DB...")
+		}
+		sentence.IsFinished = isFinished == 1
+		sentences = append(sentences, sentence)
+	}
+	return sentences, nil
+}
+
+// Replaces a Sentence's Content (moderation), finishing Sentence/Paragraph/Story as per rules (or if finish is set).
+// A finished Sentence stays finished, unless it is the last one of a Story in progress and content (e.g. after
+// deleting a Word) is not finished anymore, then it (and its Paragraph) are reopened.
+func (s *MySQLStorage) EditSentence(ctx context.Context, sentenceId int32, content string, finish bool, rules StoryRules) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.EditSentence")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
+
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted in NewTransaction
+		return err
+	}
+
+	sentence, err := GetSentenceTx(ctx, tx, sentenceId)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted in GetSentenceTx
+		return err                                    // rollback already done in getSentenceTx
+	}
+	paragraph, err := GetParagraphTx(ctx, tx, sentence.Paragraph)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return err // tx already rolled back in getParagraphTx
+	}
+
+	wasFinished := sentence.IsFinished
+	sentence.Content = content
+	sentence.IsFinished = finish || rules.SentenceFinished(content)
+	if wasFinished && !sentence.IsFinished {
+		reopen, err := s.reopenableTx(ctx, tx, sentence.ID, paragraph.Story)
+		if err != nil {
+			return err // err already logged, tx already rolledback
+		}
+		sentence.IsFinished = !reopen
+	}
+
+	if err := UpdateSentenceTx(ctx, tx, *sentence); err != nil {
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted in UpdateSentenceTx
+		return err                                    // Already rolledback in UpdateSentenceTx
+	}
+
+	// Update Story's UpdatedAt (and Content Version)
+	if err := UpdateStoryUpdateTimeTx(ctx, tx, paragraph.Story); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Updating Story's UpdatedAt")
+		// tx already rolled back in UpdateStoryUpdateTimeTx
+		return errors.New("Error Updating Story's UpdatedAt...")
+	}
+
+	if sentence.IsFinished && !wasFinished {
+		// Sentence was JUST finished by the edit
+		if _, _, err := s.finishSentenceTx(ctx, tx, paragraph, rules); err != nil {
+			return err // err already logged, tx already rolledback
+		}
+	} else if !sentence.IsFinished && wasFinished {
+		// Sentence was JUST reopened by the edit
+		if err := s.reopenParagraphTx(ctx, tx, paragraph, rules); err != nil {
+			return err // err already logged, tx already rolledback
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error executing transaction")
+		return errors.New("Errors executing transaction...")
+	}
+	return nil
+}
+
+// Can a finished Sentence be reopened ? Only the last Sentence of a Story in progress can (Transaction)
+// Words are only ever added to a Story's last Sentence, and a finished Story cannot go back to writing.
+func (s *MySQLStorage) reopenableTx(ctx context.Context, tx *sql.Tx, sentenceId int32, storyId int32) (bool, error) {
+	story, err := GetStoryTx(ctx, tx, storyId)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return false, err // tx already rolledback in GetStoryTx
+	}
+	if story.IsFinished || !story.State.InProgress() {
+		return false, nil
+	}
+	lastId, err := LastSentenceIdTx(ctx, tx, storyId)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return false, err // tx already rolledback in LastSentenceIdTx
+	}
+	return lastId == sentenceId, nil
+}
+
+// Reopens Paragraph if it has less than rules.ParagraphSentences finished Sentences, after one of them was
+// just reopened (Transaction). Its Story is in progress, so it has less than rules.StoryParagraphs already.
+func (s *MySQLStorage) reopenParagraphTx(ctx context.Context, tx *sql.Tx, paragraph *Paragraph, rules StoryRules) error {
+	if !paragraph.IsFinished {
+		return nil
+	}
+	count, err := CountFinishedSentencesTx(ctx, tx, paragraph.ID)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return err // tx already rolledback in countFinishedSentencesTx
+	}
+	if count >= rules.ParagraphSentences {
+		return nil
+	}
+	paragraph.IsFinished = false
+	if err := UpdateParagraphTx(ctx, tx, *paragraph); err != nil {
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted
+		return err                                    // tx rolledback already.
+	}
+	return nil
+}
+
+// Replaces a Story's Title (moderation), a Title still being collected is added once it has rules.TitleWords words.
+func (s *MySQLStorage) RenameStory(ctx context.Context, storyId int32, title string, rules StoryRules) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.RenameStory")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
+
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return err // error already formatted in newTransaction
+	}
+
+	story, err := GetStoryTx(ctx, tx, storyId)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return err // tx already rolledback in getStoryTx
+	}
+
+	story.Title = title
+	if !story.TitleAdded && CountWords(title) >= rules.TitleWords {
+		story.TitleAdded = true
+		if story.State == StateCollectingTitle {
+			story.State = StateWriting
+		}
+	}
+
+	if err := UpdateStoryTx(ctx, tx, *story); err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return err // err already formatted in UpdateStoryTx
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error executing transaction")
+		return errors.New("Errors executing transaction...")
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+)
+
+// Gets Stories forked from a Story from DB
+func (s *MySQLStorage) GetForks(ctx context.Context, storyId int32) ([]StoryBrief, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetForks")
+	defer span.End()
+
+	query := sq.Select(storyColumns...).From("stories").Where(sq.Eq{"parent": storyId}).OrderBy("id")
+
+	rows, err := query.RunWith(s.runner(ctx)).Query()
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Getting Forks From DB")
+		return nil, errors.New("Error Getting Forks From DB...")
+	}
+	defer rows.Close()
+
+	forks := []StoryBrief{}
+	for rows.Next() {
+		story, err := scanStory(rows)
+		if err != nil {
+			logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Story Row")
+			return nil, errors.New("Error Getting Forks From DB...")
+		}
+		forks = append(forks, briefOf(*story))
+	}
+	return forks, nil
+}
+
+// Adds a new Story (in room) to DB, copying parent's Title and content up to (and including)
+// the Sentence at sentenceIdx of the Paragraph at paragraphIdx. Paragraphs and Story are finished as per rules.
+func (s *MySQLStorage) ForkStory(ctx context.Context, parentId int32, paragraphIdx int32, sentenceIdx int32, room string, rules StoryRules) (int32, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.ForkStory")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
+
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return 0, err // error already formatted in newTransaction
+	}
+
+	parent, err := GetStoryTx(ctx, tx, parentId)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return 0, err // tx already rolledback in getStoryTx
+	}
+
+	paragraphs, err := GetStoryParagraphsTx(ctx, tx, parentId)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return 0, err // tx already rolledback
+	}
+	if paragraphIdx < 0 || int(paragraphIdx) >= len(paragraphs) {
+		tx.Rollback()
+		return 0, wrd.ErrInvalidForkPosition
+	}
+
+	var content [][]string
+	for i := int32(0); i <= paragraphIdx; i++ {
+		sentences, err := GetParagraphSentencesTx(ctx, tx, paragraphs[i].ID)
+		if err != nil {
+			logging.FromContext(ctx, s.logger).Error(err)
+			return 0, err // tx already rolledback
+		}
+		if i == paragraphIdx {
+			if sentenceIdx < 0 || int(sentenceIdx) >= len(sentences) {
+				tx.Rollback()
+				return 0, wrd.ErrInvalidForkPosition
+			}
+			sentences = sentences[:sentenceIdx+1]
+		}
+		content = append(content, sentences)
+	}
+
+	// Last Paragraph can take more Sentences unless it is full, Story is finished if all its Paragraphs are.
+	lastFinished := int32(len(content[paragraphIdx])) >= rules.ParagraphSentences
+	fork := Story{Room: room, Title: parent.Title, TitleAdded: true, State: StateWriting, Parent: parentId,
+		Prompt: parent.Prompt, Keywords: parent.Keywords}
+	if lastFinished && paragraphIdx+1 >= rules.StoryParagraphs {
+		fork.IsFinished = true
+		fork.State = StateFinished
+	}
+
+	query := sq.Insert("stories").Columns("room", "title", "titleAdded", "isFinished", "state", "parent", "prompt", "keywords").
+		Values(fork.Room, fork.Title, 1, boolToInt(fork.IsFinished), string(fork.State), fork.Parent,
+			fork.Prompt, strings.Join(fork.Keywords, ","))
+	res, err := query.RunWith(runTx(ctx, tx)).Exec()
+	if err != nil {
+		tx.Rollback()
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Adding Fork To DB")
+		return 0, errors.New("Error Adding Fork To DB...")
+	}
+	id, _ := res.LastInsertId()
+	fork.ID = int32(id)
+
+	for i, sentences := range content {
+		isFinished := i < len(content)-1 || lastFinished
+		query := sq.Insert("paragraphs").Columns("story", "isFinished").Values(fork.ID, boolToInt(isFinished))
+		res, err := query.RunWith(runTx(ctx, tx)).Exec()
+		if err != nil {
+			tx.Rollback()
+			logging.FromContext(ctx, s.logger).WithError(err).Error("Error Adding Fork Paragraph To DB")
+			return 0, errors.New("Error Adding Fork To DB...")
+		}
+		paragraphId, _ := res.LastInsertId()
+
+		for _, sentence := range sentences {
+			// Copied Sentences are all finished, the next Word starts a new one.
+			query := sq.Insert("sentences").Columns("paragraph", "isFinished", "content").Values(paragraphId, 1, sentence)
+			if _, err := query.RunWith(runTx(ctx, tx)).Exec(); err != nil {
+				tx.Rollback()
+				logging.FromContext(ctx, s.logger).WithError(err).Error("Error Adding Fork Sentence To DB")
+				return 0, errors.New("Error Adding Fork To DB...")
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error executing transaction")
+		return 0, errors.New("Errors executing transaction...")
+	}
+	return fork.ID, nil
+}
+
+func boolToInt(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
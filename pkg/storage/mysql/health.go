@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+)
+
+// Tables created by db_init/init.sql.
+var schemaTables = []string{
+	"stories", "paragraphs", "sentences", "idempotency_keys", "rooms",
+	"rejected_words", "audit_log", "vote_rounds", "vote_candidates", "votes",
+}
+
+// Checks the DB can be reached.
+func (s *MySQLStorage) Ping(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.Ping")
+	defer span.End()
+
+	if err := s.db.PingContext(ctx); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Could Not Ping DB")
+		return errors.New("Could Not Reach DB...")
+	}
+	return nil
+}
+
+// Checks every table of the schema exists (i.e. db_init scripts were run).
+func (s *MySQLStorage) CheckSchema(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.CheckSchema")
+	defer span.End()
+
+	query, args, err := sq.Select("table_name").From("information_schema.tables").
+		Where(sq.Expr("table_schema = DATABASE()")).Where(sq.Eq{"table_name": schemaTables}).ToSql()
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Unexpected Error In Creating Query")
+		return errors.New("Unexpected Error In Creating Query...")
+	}
+
+	rows, err := s.runner(ctx).Query(query, args...)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Schema From DB")
+		return errors.New("Error Reading Schema From DB...")
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool)
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Schema From DB")
+			return errors.New("Error Reading Schema From DB...")
+		}
+		found[strings.ToLower(table)] = true
+	}
+
+	var missing []string
+	for _, table := range schemaTables {
+		if !found[table] {
+			missing = append(missing, table)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New("Missing Tables: " + strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+)
+
+// Gets a stored Response for a Room's Idempotency Key from DB (only if stored after since).
+func (s *MySQLStorage) GetIdempotentResponse(ctx context.Context, room string, key string, since time.Time) (*wrd.IdempotentResponse, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetIdempotentResponse")
+	defer span.End()
+
+	query, args, err := sq.Select("room", "idemKey", "requestHash", "response", "createdAt").From("idempotency_keys").
+		Where(sq.Eq{"room": room, "idemKey": key}, sq.GtOrEq{"createdAt": since.Format(MySQLTimeFormat)}).ToSql()
+
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Unexpected Error In Creating Query")
+		return nil, errors.New("Unexpected Error In Creating Query...")
+	}
+
+	var idemRes wrd.IdempotentResponse
+	var response []byte
+	if err := s.runner(ctx).QueryRow(query, args...).Scan(
+		&idemRes.Room,
+		&idemRes.Key,
+		&idemRes.RequestHash,
+		&response,
+		&idemRes.CreatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, wrd.ErrIdempotencyKeyNotFound
+		}
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Idempotency Key From DB")
+		return nil, errors.New("Error Reading Idempotency Key From DB...")
+	}
+
+	if err := json.Unmarshal(response, &idemRes.Response); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Unmarshalling Stored Response")
+		return nil, errors.New("Error Reading Idempotency Key From DB...")
+	}
+	return &idemRes, nil
+}
+
+// Stores a Response for a Room's Idempotency Key in DB (replacing an expired one, if any).
+func (s *MySQLStorage) SaveIdempotentResponse(ctx context.Context, idemRes wrd.IdempotentResponse) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.SaveIdempotentResponse")
+	defer span.End()
+
+	response, err := json.Marshal(idemRes.Response)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Marshalling Response")
+		return errors.New("Error Saving Idempotency Key To DB...")
+	}
+
+	query := sq.Replace("idempotency_keys").Columns("room", "idemKey", "requestHash", "response", "createdAt").
+		Values(idemRes.Room, idemRes.Key, idemRes.RequestHash, response, idemRes.CreatedAt.Format(MySQLTimeFormat))
+
+	if _, err := query.RunWith(s.runner(ctx)).Exec(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Saving Idempotency Key To DB")
+		return errors.New("Error Saving Idempotency Key To DB...")
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+)
+
+// Records a rejected Word in DB (for moderators)
+func (s *MySQLStorage) AddRejectedWord(ctx context.Context, rejected moderation.RejectedWord) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.AddRejectedWord")
+	defer span.End()
+
+	query := sq.Insert("rejected_words").Columns("room", "word", "filter", "reason", "createdAt").
+		Values(rejected.Room, rejected.Word, rejected.Filter, rejected.Reason, rejected.CreatedAt.Format(MySQLTimeFormat))
+
+	if _, err := query.RunWith(s.runner(ctx)).Exec(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Saving Rejected Word To DB")
+		return errors.New("Error Saving Rejected Word To DB...")
+	}
+	return nil
+}
+
+// Gets rejected Words (latest first) from DB
+func (s *MySQLStorage) GetRejectedWords(ctx context.Context, limit int32, offset int32) ([]moderation.RejectedWord, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetRejectedWords")
+	defer span.End()
+
+	query := sq.Select("id", "room", "word", "filter", "reason", "createdAt").From("rejected_words").
+		OrderBy("id DESC").Limit(uint64(limit)).Offset(uint64(offset))
+
+	rows, err := query.RunWith(s.runner(ctx)).Query()
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Rejected Words From DB")
+		return nil, errors.New("Error Reading Rejected Words From DB...")
+	}
+	defer rows.Close()
+
+	rejectedWords := []moderation.RejectedWord{}
+	for rows.Next() {
+		var rejected moderation.RejectedWord
+		if err := rows.Scan(
+			&rejected.ID,
+			&rejected.Room,
+			&rejected.Word,
+			&rejected.Filter,
+			&rejected.Reason,
+			&rejected.CreatedAt,
+		); err != nil {
+			logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Rejected Words From DB")
+			return nil, errors.New("Error Reading Rejected Words From DB...")
+		}
+		rejectedWords = append(rejectedWords, rejected)
+	}
+	return rejectedWords, rows.Err()
+}
+
+// Records a moderator's change in DB (audit trail)
+func (s *MySQLStorage) AddAuditEntry(ctx context.Context, entry moderation.AuditEntry) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.AddAuditEntry")
+	defer span.End()
+
+	query := sq.Insert("audit_log").Columns("actor", "action", "storyId", "sentenceId", "oldContent", "newContent", "createdAt").
+		Values(entry.Actor, entry.Action, entry.StoryID, entry.SentenceID, entry.Before, entry.After, entry.CreatedAt.Format(MySQLTimeFormat))
+
+	if _, err := query.RunWith(s.runner(ctx)).Exec(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Saving Audit Entry To DB")
+		return errors.New("Error Saving Audit Entry To DB...")
+	}
+	return nil
+}
+
+// Gets moderators' changes (latest first) from DB
+func (s *MySQLStorage) GetAuditEntries(ctx context.Context, limit int32, offset int32) ([]moderation.AuditEntry, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetAuditEntries")
+	defer span.End()
+
+	query := sq.Select("id", "actor", "action", "storyId", "sentenceId", "oldContent", "newContent", "createdAt").From("audit_log").
+		OrderBy("id DESC").Limit(uint64(limit)).Offset(uint64(offset))
+
+	rows, err := query.RunWith(s.runner(ctx)).Query()
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Audit Entries From DB")
+		return nil, errors.New("Error Reading Audit Entries From DB...")
+	}
+	defer rows.Close()
+
+	entries := []moderation.AuditEntry{}
+	for rows.Next() {
+		var entry moderation.AuditEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.StoryID,
+			&entry.SentenceID,
+			&entry.Before,
+			&entry.After,
+			&entry.CreatedAt,
+		); err != nil {
+			logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Audit Entries From DB")
+			return nil, errors.New("Error Reading Audit Entries From DB...")
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
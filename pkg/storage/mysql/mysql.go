@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	log "github.com/sirupsen/logrus"
@@ -20,7 +21,7 @@ func NewMySQLStorage(connection string, logger *log.Logger) (*MySQLStorage, erro
 	var err error
 	s := new(MySQLStorage)
 	if s.db, err = initDb(connection); err != nil {
-		logger.Error("Error Connection to DB...", err)
+		logger.WithError(err).Error("Error Connection to DB...")
 		return nil, err
 	}
 	logger.Info("Connected to DB successfully...")
@@ -28,9 +29,8 @@ func NewMySQLStorage(connection string, logger *log.Logger) (*MySQLStorage, erro
 	return s, nil
 }
 
-func (s *MySQLStorage) NewTransaction() (*sql.Tx, error) {
-	ctx := context.Background()
-	tx, err := s.db.BeginTx(ctx, nil)
+func (s *MySQLStorage) NewTransaction(ctx context.Context) (*sql.Tx, error) {
+	tx, err := s.db.BeginTx(withoutCancel(ctx), nil)
 	if err != nil {
 		return nil, errors.New("Unexpected Error When Accessing DB..")
 	}
@@ -38,11 +38,30 @@ func (s *MySQLStorage) NewTransaction() (*sql.Tx, error) {
 }
 
 func initDb(connection string) (*sql.DB, error) {
-	db, err := sql.Open("mysql", connection+"?parseTime=true")
+	db, err := sql.Open("mysql", connection+"?parseTime=true&loc=Local")
 	// adding ?parseTime=true will parse sql's DATETIME to time.Time when scanning.
+	// loc=Local matches how times are written (time.Now().Format), so idle times are computed correctly.
 
 	if err != nil {
 		return nil, err
 	}
 	return db, db.Ping()
 }
+
+// Sizes the connection pool (0 durations keep connections forever, see sql.DB).
+func (s *MySQLStorage) SetPool(maxOpen int, maxIdle int, maxLifetime time.Duration, maxIdleTime time.Duration) {
+	s.db.SetMaxOpenConns(maxOpen)
+	s.db.SetMaxIdleConns(maxIdle)
+	s.db.SetConnMaxLifetime(maxLifetime)
+	s.db.SetConnMaxIdleTime(maxIdleTime)
+}
+
+// Connection pool, for its stats in metrics.
+func (s *MySQLStorage) DB() *sql.DB {
+	return s.db
+}
+
+// Closes the connection pool, once nothing uses the storage anymore (e.g. after draining requests on shutdown).
+func (s *MySQLStorage) Close() error {
+	return s.db.Close()
+}
@@ -1,9 +1,11 @@
 package mysql
 
 import (
+	"context"
 	"os"
 	"testing"
 
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/stretchr/testify/assert"
@@ -30,47 +32,48 @@ func TestNewMySQLStorage(t *testing.T) {
 
 func TestAddStory(t *testing.T) {
 	var err error
-	storyId, err = storage.AddStory()
+	storyId, err = storage.AddStory(context.Background(), DefaultRoom, Prompt{})
 	require.NoError(t, err)
 }
 
 func TestAddParagraph(t *testing.T) {
 	var err error
-	paragraphId, err = storage.AddParagraph(storyId)
+	paragraphId, err = storage.AddParagraph(context.Background(), storyId)
 	require.NoError(t, err)
 }
 
 func TestAddSentence(t *testing.T) {
 	var err error
-	sentenceId, err = storage.AddSentence(paragraphId, "First")
+	update, err := storage.AddSentence(context.Background(), paragraphId, "First", DefaultStoryRules)
 	require.NoError(t, err)
+	sentenceId = update.Sentence.ID
 }
 
 func TestGetUnfinishedStory(t *testing.T) {
-	story, err := storage.GetUnfinishedStory()
+	story, err := storage.GetUnfinishedStory(context.Background(), DefaultRoom)
 	require.NoError(t, err)
 
 	assert.Equal(t, storyId, story.ID, "Expected Story IDs To Match")
 }
 
 func TestGetUnfinishedParagrph(t *testing.T) {
-	paragraph, err := storage.GetUnfinishedParagraph(storyId)
+	paragraph, err := storage.GetUnfinishedParagraph(context.Background(), storyId)
 	require.NoError(t, err)
 
 	assert.Equal(t, paragraphId, paragraph.ID, "Expected Paragraph IDS to Match")
 }
 
 func TestGetUnfinishedSentence(t *testing.T) {
-	sentence, err := storage.GetUnfinishedSentence(paragraphId)
+	sentence, err := storage.GetUnfinishedSentence(context.Background(), paragraphId)
 	require.NoError(t, err)
 
 	assert.Equal(t, sentenceId, sentence.ID, "Expected Sentence IDs To Match")
 }
 
 func TestUpdateStoryTitle(t *testing.T) {
-	err := storage.UpdateStoryTitle(storyId, "Random")
+	err := storage.UpdateStoryTitle(context.Background(), storyId, "Random", DefaultStoryRules)
 	require.NoError(t, err)
-	err = storage.UpdateStoryTitle(storyId, "Title")
+	err = storage.UpdateStoryTitle(context.Background(), storyId, "Title", DefaultStoryRules)
 	require.NoError(t, err)
 
 	// Get Story and Check Title for Furthur Tests.
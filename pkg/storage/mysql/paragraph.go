@@ -1,26 +1,33 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
 	. "github.com/shubhamdwivedii/collab-story/pkg/paragraph"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
 )
 
 // Add a new Paragraph to DB
-func (s *MySQLStorage) AddParagraph(storyId int32) (int32, error) {
-	tx, err := s.NewTransaction()
+func (s *MySQLStorage) AddParagraph(ctx context.Context, storyId int32) (int32, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.AddParagraph")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
 	if err != nil {
-		s.logger.Error(err)
+		logging.FromContext(ctx, s.logger).Error(err)
 		return 0, err // error already formatted in NewTransaction.
 	}
 
 	// Check if Story Exists
-	story, err := GetStoryTx(tx, storyId)
+	story, err := GetStoryTx(ctx, tx, storyId)
 
 	if err != nil {
-		s.logger.Error(err)
+		logging.FromContext(ctx, s.logger).Error(err)
 		return 0, err // error already formatted in GetStoryTx
 	}
 
@@ -28,8 +35,8 @@ func (s *MySQLStorage) AddParagraph(storyId int32) (int32, error) {
 	// other values have default
 
 	var paragraphId int32
-	if res, err := query.RunWith(tx).Exec(); err != nil {
-		s.logger.Error("Error Inserting Paragraph To DB:" + err.Error())
+	if res, err := query.RunWith(runTx(ctx, tx)).Exec(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Inserting Paragraph To DB")
 		return 0, errors.New("Error Inserting Paragraph To DB:" + err.Error())
 	} else {
 		id, _ := res.LastInsertId()
@@ -37,21 +44,46 @@ func (s *MySQLStorage) AddParagraph(storyId int32) (int32, error) {
 	}
 
 	// Update Story's UpdatedAt
-	if err := UpdateStoryUpdateTimeTx(tx, story.ID); err != nil {
-		s.logger.Error("Error Updating Story's UpdatedAt:" + err.Error())
+	if err := UpdateStoryUpdateTimeTx(ctx, tx, story.ID); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Updating Story's UpdatedAt")
 		return 0, err
 	}
 
 	if err := tx.Commit(); err != nil {
-		s.logger.Error("Error Executing Transaction:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Executing Transaction")
 		return 0, errors.New("Error Executing Transaction...")
 	}
 
 	return paragraphId, nil
 }
 
+func (s *MySQLStorage) GetParagraph(ctx context.Context, paragraphId int32) (*Paragraph, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetParagraph")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
+
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted in NewTransaction
+		return nil, err
+	}
+
+	paragraph, err := GetParagraphTx(ctx, tx, paragraphId)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted in GetParagraphTx
+		return nil, err                               // rollback already done in GetParagraphTx
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error executing transaction")
+		return nil, errors.New("Errors executing transaction...")
+	}
+
+	return paragraph, nil
+}
+
 // Get Paragraph By ID (Transaction)
-func GetParagraphTx(tx *sql.Tx, paragraphId int32) (*Paragraph, error) {
+func GetParagraphTx(ctx context.Context, tx *sql.Tx, paragraphId int32) (*Paragraph, error) {
 	var paragraph Paragraph
 
 	query, args, err := sq.Select("*").From("paragraphs").Where(sq.Eq{"id": paragraphId}).ToSql()
@@ -62,7 +94,7 @@ func GetParagraphTx(tx *sql.Tx, paragraphId int32) (*Paragraph, error) {
 	}
 
 	var isFinished int32
-	if err := tx.QueryRow(query, args...).Scan(
+	if err := runTx(ctx, tx).QueryRow(query, args...).Scan(
 		&paragraph.ID,
 		&paragraph.Story,
 		&isFinished,
@@ -79,11 +111,11 @@ func GetParagraphTx(tx *sql.Tx, paragraphId int32) (*Paragraph, error) {
 }
 
 // Get All Paragraphs for a Story ID (Transaction)
-func GetStoryParagraphsTx(tx *sql.Tx, storyId int32) ([]Paragraph, error) {
+func GetStoryParagraphsTx(ctx context.Context, tx *sql.Tx, storyId int32) ([]Paragraph, error) {
 	var paragraphs []Paragraph
 
-	query := sq.Select("*").From("paragraphs").Where(sq.Eq{"story": storyId})
-	rows, err := query.RunWith(tx).Query()
+	query := sq.Select("*").From("paragraphs").Where(sq.Eq{"story": storyId}).OrderBy("id")
+	rows, err := query.RunWith(runTx(ctx, tx)).Query()
 
 	if err != nil {
 		tx.Rollback()
@@ -109,7 +141,7 @@ func GetStoryParagraphsTx(tx *sql.Tx, storyId int32) ([]Paragraph, error) {
 }
 
 // Update a Paragraph (TX)
-func UpdateParagraphTx(tx *sql.Tx, paragraph Paragraph) error {
+func UpdateParagraphTx(ctx context.Context, tx *sql.Tx, paragraph Paragraph) error {
 	var isFinished int32
 	if paragraph.IsFinished {
 		isFinished = 1
@@ -124,30 +156,36 @@ func UpdateParagraphTx(tx *sql.Tx, paragraph Paragraph) error {
 		return errors.New("Error Generating Paragraph Update Query:" + err.Error())
 	}
 
-	if _, err = tx.Exec(query, args...); err != nil {
+	if _, err = runTx(ctx, tx).Exec(query, args...); err != nil {
 		tx.Rollback()
 		return errors.New("Error Updating Paragraph in DB:" + err.Error())
 	}
 	return nil
 }
 
-func (s *MySQLStorage) GetUnfinishedParagraph(storyId int32) (*Paragraph, error) {
+func (s *MySQLStorage) GetUnfinishedParagraph(ctx context.Context, storyId int32) (*Paragraph, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetUnfinishedParagraph")
+	defer span.End()
+
 	var paragraph Paragraph
 
 	query, args, err := sq.Select("*").From("paragraphs").Where(sq.Eq{"isFinished": 0}, sq.Eq{"story": storyId}).ToSql()
 
 	if err != nil {
-		s.logger.Error("Unexpected Error In Creating Query:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Unexpected Error In Creating Query")
 		return nil, errors.New("Unexpected Error In Creating Query...")
 	}
 
 	var isFinished int32
-	if err := s.db.QueryRow(query, args...).Scan(
+	if err := s.runner(ctx).QueryRow(query, args...).Scan(
 		&paragraph.ID,
 		&paragraph.Story,
 		&isFinished,
 	); err != nil {
-		s.logger.Info("Cannot Find An Unfinished Paragraph in DB:" + err.Error())
+		if err == sql.ErrNoRows {
+			return nil, wrd.ErrParagraphNotFound
+		}
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Unfinished Paragraph From DB")
 		return nil, errors.New("Cannot Find An Unfinished Paragraph in DB...")
 	}
 
@@ -158,7 +196,7 @@ func (s *MySQLStorage) GetUnfinishedParagraph(storyId int32) (*Paragraph, error)
 	return &paragraph, nil
 }
 
-func CountFinishedSentencesTx(tx *sql.Tx, paragraphId int32) (int32, error) {
+func CountFinishedSentencesTx(ctx context.Context, tx *sql.Tx, paragraphId int32) (int32, error) {
 	query, args, err := sq.Select("count(*) as count").From("sentences").
 		Where(sq.Eq{"paragraph": paragraphId}, sq.Eq{"isFinished": 1}).ToSql()
 
@@ -168,7 +206,7 @@ func CountFinishedSentencesTx(tx *sql.Tx, paragraphId int32) (int32, error) {
 	}
 
 	var count int32
-	if err := tx.QueryRow(query, args...).Scan(
+	if err := runTx(ctx, tx).QueryRow(query, args...).Scan(
 		&count,
 	); err != nil {
 		tx.Rollback()
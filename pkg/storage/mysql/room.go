@@ -0,0 +1,103 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+)
+
+// Gets a Room (and its StoryRules and prompt) from DB
+func (s *MySQLStorage) GetRoom(ctx context.Context, name string) (*Room, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetRoom")
+	defer span.End()
+
+	query, args, err := sq.Select("name", "titleWords", "sentenceWords", "minSentenceWords", "paragraphSentences", "storyParagraphs", "voteSeconds", "prompt", "keywords").
+		From("rooms").Where(sq.Eq{"name": name}).ToSql()
+
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Unexpected Error In Creating Query")
+		return nil, errors.New("Unexpected Error In Creating Query...")
+	}
+
+	var room Room
+	var prompt Prompt
+	var keywords string
+	if err := s.runner(ctx).QueryRow(query, args...).Scan(
+		&room.Name,
+		&room.Rules.TitleWords,
+		&room.Rules.SentenceWords,
+		&room.Rules.MinSentenceWords,
+		&room.Rules.ParagraphSentences,
+		&room.Rules.StoryParagraphs,
+		&room.Rules.VoteSeconds,
+		&prompt.Text,
+		&keywords,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRoomNotFound
+		}
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Room From DB")
+		return nil, errors.New("Error Reading Room From DB...")
+	}
+	if keywords != "" {
+		prompt.Keywords = strings.Split(keywords, ",")
+	}
+	if prompt.Text != "" || len(prompt.Keywords) > 0 {
+		room.Prompt = &prompt
+	}
+	return &room, nil
+}
+
+// Creates a Room in DB, or updates its StoryRules (keeping its prompt).
+func (s *MySQLStorage) SaveRoom(ctx context.Context, room Room) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.SaveRoom")
+	defer span.End()
+
+	text, keywords := promptColumns(room.Prompt)
+	query := sq.Insert("rooms").
+		Columns("name", "titleWords", "sentenceWords", "minSentenceWords", "paragraphSentences", "storyParagraphs", "voteSeconds", "prompt", "keywords").
+		Values(room.Name, room.Rules.TitleWords, room.Rules.SentenceWords, room.Rules.MinSentenceWords,
+			room.Rules.ParagraphSentences, room.Rules.StoryParagraphs, room.Rules.VoteSeconds, text, keywords).
+		Suffix("ON DUPLICATE KEY UPDATE titleWords = VALUES(titleWords), sentenceWords = VALUES(sentenceWords), " +
+			"minSentenceWords = VALUES(minSentenceWords), paragraphSentences = VALUES(paragraphSentences), " +
+			"storyParagraphs = VALUES(storyParagraphs), voteSeconds = VALUES(voteSeconds)")
+
+	if _, err := query.RunWith(s.runner(ctx)).Exec(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Saving Room To DB")
+		return errors.New("Error Saving Room To DB...")
+	}
+	return nil
+}
+
+// Sets (or clears, if nil) the prompt of a Room's next Story in DB, creating the Room with rules if needed.
+func (s *MySQLStorage) SaveRoomPrompt(ctx context.Context, room string, rules StoryRules, prompt *Prompt) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.SaveRoomPrompt")
+	defer span.End()
+
+	text, keywords := promptColumns(prompt)
+	query := sq.Insert("rooms").
+		Columns("name", "titleWords", "sentenceWords", "minSentenceWords", "paragraphSentences", "storyParagraphs", "voteSeconds", "prompt", "keywords").
+		Values(room, rules.TitleWords, rules.SentenceWords, rules.MinSentenceWords,
+			rules.ParagraphSentences, rules.StoryParagraphs, rules.VoteSeconds, text, keywords).
+		Suffix("ON DUPLICATE KEY UPDATE prompt = VALUES(prompt), keywords = VALUES(keywords)")
+
+	if _, err := query.RunWith(s.runner(ctx)).Exec(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Saving Room Prompt To DB")
+		return errors.New("Error Saving Room Prompt To DB...")
+	}
+	return nil
+}
+
+// Prompt and keywords columns of a (maybe nil) prompt.
+func promptColumns(prompt *Prompt) (string, string) {
+	if prompt == nil {
+		return "", ""
+	}
+	return prompt.Text, strings.Join(prompt.Keywords, ",")
+}
@@ -1,101 +1,130 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"strings"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/paragraph"
 	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
 )
 
-// Add a new Sentence to DB
-func (s *MySQLStorage) AddSentence(paragraphId int32, word string) (int32, error) {
+// Add a new Sentence to DB (finishing it right away, along with Paragraph/Story, as per rules)
+func (s *MySQLStorage) AddSentence(ctx context.Context, paragraphId int32, word string, rules StoryRules) (*wrd.SentenceUpdate, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.AddSentence")
+	defer span.End()
+
+	if IsPunctuation(word) {
+		return nil, wrd.ErrPunctuationOnly
+	}
+
 	// Check if paragraph exists
-	tx, err := s.NewTransaction()
+	tx, err := s.NewTransaction(ctx)
 
 	if err != nil {
-		s.logger.Error(err)
-		return 0, err // err already formatted in NewTransaction
+		logging.FromContext(ctx, s.logger).Error(err)
+		return nil, err // err already formatted in NewTransaction
 	}
 
-	paragraph, err := GetParagraphTx(tx, paragraphId)
+	paragraph, err := GetParagraphTx(ctx, tx, paragraphId)
 	if err != nil {
-		s.logger.Error(err)
-		return 0, err // err already formatted in GetParagraphTx
+		logging.FromContext(ctx, s.logger).Error(err)
+		return nil, err // err already formatted in GetParagraphTx
 	}
 
-	query := sq.Insert("sentences").Columns("paragraph", "content").Values(paragraphId, word)
-	// other values have default
+	sentence := Sentence{Paragraph: paragraphId, Content: word, IsFinished: rules.SentenceFinished(word)}
+	var isFinished int32
+	if sentence.IsFinished {
+		isFinished = 1
+	}
+	query := sq.Insert("sentences").Columns("paragraph", "content", "isFinished").Values(paragraphId, word, isFinished)
 
-	var sentenceId int32
-	if res, err := query.RunWith(tx).Exec(); err != nil {
+	if res, err := query.RunWith(runTx(ctx, tx)).Exec(); err != nil {
 		tx.Rollback()
-		s.logger.Error("Error Adding Sentence To DB:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Adding Sentence To DB")
 		// Abstract DB error messages.
-		return 0, errors.New("Error Inserting Sentence To DB...")
+		return nil, errors.New("Error Inserting Sentence To DB...")
 	} else {
 		id, _ := res.LastInsertId()
-		sentenceId = int32(id)
+		sentence.ID = int32(id)
 	}
 
 	// Update Story's UpdatedAt
-	if err := UpdateStoryUpdateTimeTx(tx, paragraph.Story); err != nil {
-		tx.Rollback()
-		s.logger.Error("Error Updating Story's UpdatedAt:" + err.Error())
-		return 0, errors.New("Error Updating Story's UpdatedAt...")
+	if err := UpdateStoryUpdateTimeTx(ctx, tx, paragraph.Story); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Updating Story's UpdatedAt")
+		// tx already rolled back in UpdateStoryUpdateTimeTx
+		return nil, errors.New("Error Updating Story's UpdatedAt...")
+	}
+
+	update, err := s.sentenceUpdateTx(ctx, tx, sentence, paragraph, rules)
+	if err != nil {
+		return nil, err // err already logged, tx already rolledback
 	}
 
 	if err := tx.Commit(); err != nil {
-		s.logger.Error("Error Commiting Transaction:" + err.Error())
-		return 0, errors.New("Error Commiting Transaction...")
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Commiting Transaction")
+		return nil, errors.New("Error Commiting Transaction...")
 	}
 
-	return sentenceId, nil
+	return update, nil
 }
 
-func (s *MySQLStorage) GetSentence(sentenceId int32) (*Sentence, error) {
-	tx, err := s.NewTransaction()
+func (s *MySQLStorage) GetSentence(ctx context.Context, sentenceId int32) (*Sentence, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetSentence")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
 
 	if err != nil {
-		s.logger.Error(err) // err already formatted in NewTransaction
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted in NewTransaction
 		return nil, err
 	}
 
 	// Check if Sentence exists.
-	sentence, err := GetSentenceTx(tx, sentenceId)
+	sentence, err := GetSentenceTx(ctx, tx, sentenceId)
 	if err != nil {
-		s.logger.Error(err) // err already formatted in GetSentenceTx
-		return nil, err     // rollback already done in getSentenceTx
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted in GetSentenceTx
+		return nil, err                               // rollback already done in getSentenceTx
 	}
 
 	if err := tx.Commit(); err != nil {
-		s.logger.Error("Error executing transaction:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error executing transaction")
 		return nil, errors.New("Errors executing transaction...")
 	}
 
 	return sentence, nil
 }
 
-func (s *MySQLStorage) GetUnfinishedSentence(paragraphId int32) (*Sentence, error) {
+func (s *MySQLStorage) GetUnfinishedSentence(ctx context.Context, paragraphId int32) (*Sentence, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetUnfinishedSentence")
+	defer span.End()
+
 	var sentence Sentence
 
 	query, args, err := sq.Select("*").From("sentences").
 		Where(sq.Eq{"isFinished": 0}, sq.Eq{"paragraph": paragraphId}).ToSql()
 
 	if err != nil {
-		s.logger.Error("Unexpected Error In Creating Query:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Unexpected Error In Creating Query")
 		return nil, errors.New("Unexpected Error In Creating Query...")
 	}
 
 	var isFinished int32
-	if err := s.db.QueryRow(query, args...).Scan(
+	if err := s.runner(ctx).QueryRow(query, args...).Scan(
 		&sentence.ID,
 		&sentence.Paragraph,
 		&isFinished,
 		&sentence.Content,
 	); err != nil {
-		s.logger.Info("Cannot Find An Unfinished Sentence in DB:" + err.Error())
+		if err == sql.ErrNoRows {
+			return nil, wrd.ErrSentenceNotFound
+		}
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Unfinished Sentence From DB")
 		return nil, errors.New("Cannot Find An Unfinished Sentence in DB...")
 	}
 
@@ -106,114 +135,154 @@ func (s *MySQLStorage) GetUnfinishedSentence(paragraphId int32) (*Sentence, erro
 	return &sentence, nil
 }
 
-// Updates a Sentence's Content (Words)
-func (s *MySQLStorage) UpdateSentence(sentenceId int32, word string) error {
-	tx, err := s.NewTransaction()
+// Updates a Sentence's Content (Words), finishing Sentence/Paragraph/Story as per rules.
+func (s *MySQLStorage) UpdateSentence(ctx context.Context, sentenceId int32, word string, rules StoryRules) (*wrd.SentenceUpdate, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.UpdateSentence")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
 
 	if err != nil {
-		s.logger.Error(err) // err already formatted in NewTransaction
-		return err
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted in NewTransaction
+		return nil, err
 	}
 
 	// Check if Sentence exists.
-	sentence, err := GetSentenceTx(tx, sentenceId)
+	sentence, err := GetSentenceTx(ctx, tx, sentenceId)
 	if err != nil {
-		s.logger.Error(err) // err already formatted in GetSentenceTx
-		return err          // rollback already done in getSentenceTx
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted in GetSentenceTx
+		return nil, err                               // rollback already done in getSentenceTx
 	}
 
 	// Check if Sentence is already finished
-	words := strings.Split(sentence.Content, " ")
-	if len(words) >= 15 || sentence.IsFinished {
-		return errors.New("Error: Sentence Is Already Finished")
+	if CountWords(sentence.Content) >= rules.SentenceWords || sentence.IsFinished {
+		tx.Rollback()
+		return nil, errors.New("Error: Sentence Is Already Finished")
 	} else {
-		// Not Finished, Add one more Word.
-		sentence.Content = sentence.Content + " " + word
-		if len(words)+1 == 15 { // Is it finished now ?
-			sentence.IsFinished = true
-		}
+		// Not Finished, Add one more Word (or attach punctuation to the last one).
+		sentence.Content = AppendWord(sentence.Content, word)
+		// Is it finished now ? (full, or ended with punctuation after enough words)
+		sentence.IsFinished = rules.SentenceFinished(sentence.Content)
 	}
 
 	// Update Sentence's Content or IsFinished status.
-	if err := UpdateSentenceTx(tx, *sentence); err != nil {
-		s.logger.Error(err) // err already formatted in UpdateSentenceTx
-		return err          // Already rolledback in UpdateSentenceTx
+	if err := UpdateSentenceTx(ctx, tx, *sentence); err != nil {
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted in UpdateSentenceTx
+		return nil, err                               // Already rolledback in UpdateSentenceTx
+	}
+
+	// Update Story's UpdatedAt (and Content Version)
+	paragraph, err := GetParagraphTx(ctx, tx, sentence.Paragraph)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		// tx already rolled back in getParagraphTx
+		return nil, err
+	}
+	if err := UpdateStoryUpdateTimeTx(ctx, tx, paragraph.Story); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Updating Story's UpdatedAt")
+		// tx already rolled back in UpdateStoryUpdateTimeTx
+		return nil, errors.New("Error Updating Story's UpdatedAt...")
+	}
+
+	update, err := s.sentenceUpdateTx(ctx, tx, *sentence, paragraph, rules)
+	if err != nil {
+		return nil, err // err already logged, tx already rolledback
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error executing transaction")
+		return nil, errors.New("Errors executing transaction...")
 	}
 
-	// If Sentence was JUST marked finished (with the last word added)
+	return update, nil
+}
+
+// What adding a Word to sentence did, finishing Paragraph/Story if the Word JUST finished sentence (Transaction)
+func (s *MySQLStorage) sentenceUpdateTx(ctx context.Context, tx *sql.Tx, sentence Sentence, paragraph *Paragraph, rules StoryRules) (*wrd.SentenceUpdate, error) {
+	update := &wrd.SentenceUpdate{Sentence: sentence}
 	if sentence.IsFinished {
-		// Check if Paragraph is Finished (has 10 sentences now)
-		count, err := CountFinishedSentencesTx(tx, sentence.Paragraph)
-		if err != nil {
-			s.logger.Error(err)
-			// Tx already rolledback in countFinishedSentencesTx
-			return err
+		var err error
+		if update.ParagraphFinished, update.StoryFinished, err = s.finishSentenceTx(ctx, tx, paragraph, rules); err != nil {
+			return nil, err // err already logged, tx already rolledback
 		}
+	} else {
+		// Nothing Sentence can have more words
+	}
 
-		if count >= 10 {
-			// Check if Paragraph is finished already or not then update isFinished status.
-			paragraph, err := GetParagraphTx(tx, sentence.Paragraph)
-			if err != nil {
-				s.logger.Error(err)
-				// tx already rolled back in getParagraphTx
-				return err
+	// Story's Version after the Word
+	story, err := GetStoryTx(ctx, tx, paragraph.Story)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return nil, err // tx already rolledback in GetStoryTx
+	}
+	update.Version = story.Version
+	return update, nil
+}
+
+// Finishes Paragraph (and Story) as per rules, after one of Paragraph's Sentences was just finished (Transaction)
+// Returns whether the Paragraph and the Story were finished.
+func (s *MySQLStorage) finishSentenceTx(ctx context.Context, tx *sql.Tx, paragraph *Paragraph, rules StoryRules) (bool, bool, error) {
+	var paragraphFinished, storyFinished bool
+
+	// Check if Paragraph is Finished (has rules.ParagraphSentences sentences now)
+	count, err := CountFinishedSentencesTx(ctx, tx, paragraph.ID)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		// Tx already rolledback in countFinishedSentencesTx
+		return false, false, err
+	}
+
+	if count >= rules.ParagraphSentences {
+		// Check if Paragraph is finished already or not then update isFinished status.
+		if !paragraph.IsFinished {
+			// Mark Paragraph as finished.
+			paragraph.IsFinished = true
+			if err := UpdateParagraphTx(ctx, tx, *paragraph); err != nil {
+				logging.FromContext(ctx, s.logger).Error(err) // err already formatted
+				// tx rolledback already.
+				return false, false, err
 			}
+			paragraphFinished = true
 
-			if !paragraph.IsFinished {
-				// Mark Paragraph as finished.
-				paragraph.IsFinished = true
-				if err := UpdateParagraphTx(tx, *paragraph); err != nil {
-					s.logger.Error(err) // err already formatted
-					// tx rolledback already.
-					return err
-				}
+			// Now Check if Story is finished now (with last Paragraph marked Finished)
+			count, err := CountFinishedParagraphsTx(ctx, tx, paragraph.Story)
+			if err != nil {
+				logging.FromContext(ctx, s.logger).Error(err)
+				// tx already rolledback in countFinishedParagraphsTx
+				return false, false, err
+			}
 
-				// Now Check if Story is finished now (with last Paragraph marked Finished)
-				count, err := CountFinishedParagraphsTx(tx, paragraph.Story)
+			if count >= rules.StoryParagraphs {
+				// Check if Story is marked finished already or not, update isFinished.
+				story, err := GetStoryTx(ctx, tx, paragraph.Story)
 				if err != nil {
-					s.logger.Error(err)
-					// tx already rolledback in countFinishedParagraphsTx
-					return err
+					logging.FromContext(ctx, s.logger).Error(err)
+					return false, false, err
 				}
 
-				if count >= 7 {
-					// Check if Story is marked finished already or not, update isFinished.
-					story, err := GetStoryTx(tx, paragraph.Story)
-					if err != nil {
-						s.logger.Error(err)
-						return err
-					}
-
-					if !story.IsFinished {
-						// Mark Story as Finished.
-						story.IsFinished = true
-						if err := UpdateStoryTx(tx, *story); err != nil {
-							s.logger.Error(err)
-							// tx rolledback
-							return err
-						}
+				if !story.IsFinished {
+					// Mark Story as Finished.
+					story.IsFinished = true
+					story.State = StateFinished
+					if err := UpdateStoryTx(ctx, tx, *story); err != nil {
+						logging.FromContext(ctx, s.logger).Error(err)
+						// tx rolledback
+						return false, false, err
 					}
-				} else {
-					// Nothing Finished Paragraphs is still less than 10.
+					storyFinished = true
 				}
+			} else {
+				// Nothing Finished Paragraphs is still less than rules.StoryParagraphs.
 			}
-		} else {
-			// Nothing Finished Sentences is still less than 10.
 		}
 	} else {
-		// Nothing Sentence can have more words
-	}
-
-	if err := tx.Commit(); err != nil {
-		s.logger.Error("Error executing transaction:" + err.Error())
-		return errors.New("Errors executing transaction...")
+		// Nothing Finished Sentences is still less than rules.ParagraphSentences.
 	}
-
-	return nil
+	return paragraphFinished, storyFinished, nil
 }
 
 // Get Sentence By ID (Transaction)
-func GetSentenceTx(tx *sql.Tx, sentenceId int32) (*Sentence, error) {
+func GetSentenceTx(ctx context.Context, tx *sql.Tx, sentenceId int32) (*Sentence, error) {
 	var sentence Sentence
 
 	query, args, err := sq.Select("*").From("sentences").Where(sq.Eq{"id": sentenceId}).ToSql()
@@ -225,7 +294,7 @@ func GetSentenceTx(tx *sql.Tx, sentenceId int32) (*Sentence, error) {
 
 	var isFinished int
 
-	if err := tx.QueryRow(query, args...).Scan(
+	if err := runTx(ctx, tx).QueryRow(query, args...).Scan(
 		&sentence.ID,
 		&sentence.Paragraph,
 		&isFinished,
@@ -242,12 +311,33 @@ func GetSentenceTx(tx *sql.Tx, sentenceId int32) (*Sentence, error) {
 	return &sentence, nil
 }
 
+// ID of a Story's last Sentence, 0 if it has none (Transaction)
+func LastSentenceIdTx(ctx context.Context, tx *sql.Tx, storyId int32) (int32, error) {
+	query, args, err := sq.Select("COALESCE(MAX(s.id), 0)").From("sentences s").
+		Join("paragraphs p ON p.id = s.paragraph").
+		Where(sq.Eq{"p.story": storyId}).ToSql()
+
+	if err != nil {
+		tx.Rollback()
+		return 0, errors.New("Cannot Find Last Sentence" + err.Error())
+	}
+
+	var id int32
+	if err := runTx(ctx, tx).QueryRow(query, args...).Scan(
+		&id,
+	); err != nil {
+		tx.Rollback()
+		return 0, errors.New("Cannot Find Last Sentence" + err.Error())
+	}
+	return id, nil
+}
+
 // Get All Sentences for a Paragraph ID (Transaction)
-func GetParagraphSentencesTx(tx *sql.Tx, paragraphId int32) ([]string, error) {
+func GetParagraphSentencesTx(ctx context.Context, tx *sql.Tx, paragraphId int32) ([]string, error) {
 	var sentences []string
 
-	query := sq.Select("content").From("sentences").Where(sq.Eq{"paragraph": paragraphId})
-	rows, err := query.RunWith(tx).Query()
+	query := sq.Select("content").From("sentences").Where(sq.Eq{"paragraph": paragraphId}).OrderBy("id")
+	rows, err := query.RunWith(runTx(ctx, tx)).Query()
 
 	if err != nil {
 		tx.Rollback()
@@ -270,7 +360,7 @@ func GetParagraphSentencesTx(tx *sql.Tx, paragraphId int32) ([]string, error) {
 }
 
 // Update A Sentence (Transaction)
-func UpdateSentenceTx(tx *sql.Tx, sentence Sentence) error {
+func UpdateSentenceTx(ctx context.Context, tx *sql.Tx, sentence Sentence) error {
 	var isFinished int32
 	if sentence.IsFinished {
 		isFinished = 1
@@ -285,7 +375,7 @@ func UpdateSentenceTx(tx *sql.Tx, sentence Sentence) error {
 		return errors.New("Error Generating Sentence Update Query:" + err.Error())
 	}
 
-	if _, err = tx.Exec(query, args...); err != nil {
+	if _, err = runTx(ctx, tx).Exec(query, args...); err != nil {
 		tx.Rollback()
 		return errors.New("Error Updating Sentence In DB:" + err.Error())
 	}
@@ -1,23 +1,73 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"regexp"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
-
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
 	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
 )
 
-// Add a new Story to DB
-func (s *MySQLStorage) AddStory() (int32, error) {
-	query := sq.Insert("stories").Columns().Values()
-	// All values have defaults
+// Columns selected for a Story (in scanStory order).
+var storyColumns = []string{"id", "room", "title", "titleAdded", "isFinished", "state", "version", "parent", "prompt", "keywords", "createdAt", "updatedAt"}
+
+// Anything a Story can be scanned from (*sql.Row or *sql.Rows).
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a Story selected with storyColumns.
+func scanStory(row rowScanner) (*Story, error) {
+	var story Story
+	var titleAdded, isFinished int32
+	var keywords string
+	if err := row.Scan(
+		&story.ID,
+		&story.Room,
+		&story.Title,
+		&titleAdded,
+		&isFinished,
+		&story.State,
+		&story.Version,
+		&story.Parent,
+		&story.Prompt,
+		&keywords,
+		&story.CreatedAt,
+		&story.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
 
-	if res, err := query.RunWith(s.db).Exec(); err != nil {
-		s.logger.Error("Error Adding Story To DB:", err.Error())
+	if titleAdded == 1 {
+		story.TitleAdded = true
+	}
+	if isFinished == 1 {
+		story.IsFinished = true
+	}
+	if keywords != "" {
+		story.Keywords = strings.Split(keywords, ",")
+	}
+	return &story, nil
+}
+
+// Add a new Story (in a Room, seeded with a Prompt) to DB
+func (s *MySQLStorage) AddStory(ctx context.Context, room string, prompt Prompt) (int32, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.AddStory")
+	defer span.End()
+
+	query := sq.Insert("stories").Columns("room", "prompt", "keywords").
+		Values(room, prompt.Text, strings.Join(prompt.Keywords, ","))
+	// other values have defaults
+
+	if res, err := query.RunWith(s.runner(ctx)).Exec(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Adding Story To DB")
 		// Abstract the internal DB error messages.
 		return 0, errors.New("Error Adding Story Into DB.")
 	} else {
@@ -26,91 +76,98 @@ func (s *MySQLStorage) AddStory() (int32, error) {
 	}
 }
 
-func (s *MySQLStorage) GetStory(storyId int32) (*Story, error) {
-	tx, err := s.NewTransaction()
+func (s *MySQLStorage) GetStory(ctx context.Context, storyId int32) (*Story, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetStory")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
 
 	if err != nil {
-		s.logger.Error(err)
+		logging.FromContext(ctx, s.logger).Error(err)
 		return nil, err // error already formatted in newTransaction
 	}
 
 	// Check if Story exists
-	story, err := GetStoryTx(tx, storyId)
+	story, err := GetStoryTx(ctx, tx, storyId)
 
 	if err != nil {
-		s.logger.Error(err)
+		logging.FromContext(ctx, s.logger).Error(err)
 		return nil, err // tx already rolledback in getStoryTx
 	}
 
 	if err := tx.Commit(); err != nil {
-		s.logger.Error("Error executing transaction:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error executing transaction")
 		return nil, errors.New("Errors executing transaction...")
 	}
 	return story, nil
 }
 
-// Gets All Stories from the DB.
-func (s *MySQLStorage) GetAllStories(limit int32, offset int32) (*StoriesResponse, error) {
-	tx, err := s.NewTransaction()
+// Gets All Stories (matching filter) from the DB.
+func (s *MySQLStorage) GetAllStories(ctx context.Context, filter StoryFilter) (*StoriesResponse, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetAllStories")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
 
 	if err != nil {
-		s.logger.Error(err) // err already formatted.
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted.
 		return nil, err
 	}
 
 	var stories []StoryBrief
-	query := sq.Select("*").From("stories").Limit(uint64(limit)).Offset(uint64(offset))
-	rows, err := query.RunWith(tx).Query()
+	states := make([]string, len(filter.States))
+	for i, state := range filter.States {
+		states[i] = string(state)
+	}
+
+	where := sq.And{sq.Eq{"state": states}}
+	if filter.Room != "" {
+		where = append(where, sq.Eq{"room": filter.Room})
+	}
+
+	query := sq.Select(storyColumns...).From("stories").Where(where).
+		Limit(uint64(filter.Limit)).Offset(uint64(filter.Offset))
+	rows, err := query.RunWith(runTx(ctx, tx)).Query()
 	if err != nil {
 		tx.Rollback()
-		s.logger.Error("Error Getting Stories From DB:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Getting Stories From DB")
 		return nil, err
 	}
 
 	for rows.Next() {
-		var story StoryBrief
-		var isFinished, titleAdded int32
-		err = rows.Scan(
-			&story.ID,
-			&story.Title,
-			&titleAdded,
-			&isFinished,
-			&story.CreatedAt,
-			&story.UpdatedAt,
-		)
-
+		story, err := scanStory(rows)
 		if err != nil {
 			tx.Rollback()
-			s.logger.Error("Error Reading Story Row:" + err.Error())
+			logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Story Row")
 			return nil, err
 		}
-		stories = append(stories, story)
+		stories = append(stories, briefOf(*story))
 	}
 
 	// Also Get Count Of Total Stories.
-	qry, args, err := sq.Select("count(*) as count").From("stories").ToSql()
+	qry, args, err := sq.Select("count(*) as count").From("stories").Where(where).ToSql()
 	if err != nil {
 		tx.Rollback()
-		s.logger.Error("Error Getting Cound of Stories:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Getting Cound of Stories")
 		return nil, errors.New("Cannof Build Query for Count")
 	}
 
 	var count int32
-	if err := tx.QueryRow(qry, args...).Scan(
+	if err := runTx(ctx, tx).QueryRow(qry, args...).Scan(
 		&count,
 	); err != nil {
 		tx.Rollback()
-		s.logger.Error("Error Reading Count from Row:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Count from Row")
 		return nil, errors.New("Error getting count")
 	}
 
 	if err := tx.Commit(); err != nil {
-		s.logger.Error("Error Committing Transaction:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Committing Transaction")
 		return nil, errors.New("Error Executing Transaction...")
 	} else {
 		storyRes := StoriesResponse{
-			Limit:   limit,
-			Offset:  offset,
+			Limit:   filter.Limit,
+			Offset:  filter.Offset,
 			Count:   count,
 			Results: stories,
 		}
@@ -119,24 +176,27 @@ func (s *MySQLStorage) GetAllStories(limit int32, offset int32) (*StoriesRespons
 }
 
 // Get Story's Detail By ID from DB.
-func (s *MySQLStorage) GetStoryDetail(storyId int32) (*StoryResponse, error) {
-	tx, err := s.NewTransaction()
+func (s *MySQLStorage) GetStoryDetail(ctx context.Context, storyId int32) (*StoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetStoryDetail")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
 	if err != nil {
-		s.logger.Error(err) // err already formatted
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted
 		return nil, err
 	}
 
-	story, err := GetStoryTx(tx, storyId)
+	story, err := GetStoryTx(ctx, tx, storyId)
 	if err != nil {
-		s.logger.Error(err)
+		logging.FromContext(ctx, s.logger).Error(err)
 		return nil, err
 	}
 
 	// Get all Paragraphs for the Story.
-	paragraphs, err := GetStoryParagraphsTx(tx, storyId)
+	paragraphs, err := GetStoryParagraphsTx(ctx, tx, storyId)
 
 	if err != nil {
-		s.logger.Error(err) // err already formatted
+		logging.FromContext(ctx, s.logger).Error(err) // err already formatted
 		return nil, err
 	}
 
@@ -144,9 +204,9 @@ func (s *MySQLStorage) GetStoryDetail(storyId int32) (*StoryResponse, error) {
 	var paraBriefs []ParagraphBrief
 
 	for _, para := range paragraphs {
-		sentences, err := GetParagraphSentencesTx(tx, para.ID)
+		sentences, err := GetParagraphSentencesTx(ctx, tx, para.ID)
 		if err != nil {
-			s.logger.Error(err) // err already formatted
+			logging.FromContext(ctx, s.logger).Error(err) // err already formatted
 			return nil, err
 		}
 		paragraph := ParagraphBrief{
@@ -156,9 +216,34 @@ func (s *MySQLStorage) GetStoryDetail(storyId int32) (*StoryResponse, error) {
 		paraBriefs = append(paraBriefs, paragraph)
 	}
 
+	// Stories this one was forked from (each fork has its own Room, so there are no cycles).
+	var lineage []int32
+	for parent := story.Parent; parent != 0; {
+		ancestor, err := GetStoryTx(ctx, tx, parent)
+		if err != nil {
+			logging.FromContext(ctx, s.logger).Error(err)
+			return nil, err // tx already rolledback in getStoryTx
+		}
+		lineage = append(lineage, ancestor.ID)
+		parent = ancestor.Parent
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Committing Transaction")
+		return nil, errors.New("Error Executing Transaction...")
+	}
+
 	storyRes := StoryResponse{
 		ID:         story.ID,
+		Room:       story.Room,
 		Title:      story.Title,
+		State:      story.State,
+		Version:    story.Version,
+		IsFinished: story.IsFinished,
+		Parent:     story.Parent,
+		Lineage:    lineage,
+		Prompt:     story.Prompt,
+		Keywords:   story.Keywords,
 		CreatedAt:  story.CreatedAt,
 		UpdatedAt:  story.UpdatedAt,
 		Paragraphs: paraBriefs,
@@ -166,42 +251,41 @@ func (s *MySQLStorage) GetStoryDetail(storyId int32) (*StoryResponse, error) {
 	return &storyRes, nil
 }
 
-// Get Story from DB (Transaction)
-func GetStoryTx(tx *sql.Tx, storyId int32) (*Story, error) {
-	var story Story
+func briefOf(story Story) StoryBrief {
+	return StoryBrief{
+		ID:        story.ID,
+		Room:      story.Room,
+		Title:     story.Title,
+		State:     story.State,
+		Version:   story.Version,
+		Parent:    story.Parent,
+		Prompt:    story.Prompt,
+		CreatedAt: story.CreatedAt,
+		UpdatedAt: story.UpdatedAt,
+	}
+}
 
-	query, args, err := sq.Select("*").From("stories").Where(sq.Eq{"id": storyId}).ToSql()
+// Get Story from DB (Transaction)
+func GetStoryTx(ctx context.Context, tx *sql.Tx, storyId int32) (*Story, error) {
+	query, args, err := sq.Select(storyColumns...).From("stories").Where(sq.Eq{"id": storyId}).ToSql()
 
 	if err != nil {
 		tx.Rollback()
 		return nil, errors.New("Unexpected Error In Creating Query:" + err.Error())
 	}
 
-	var titleAdded, isFinished int
-	if err := tx.QueryRow(query, args...).Scan(
-		&story.ID,
-		&story.Title,
-		&titleAdded,
-		&isFinished,
-		&story.CreatedAt,
-		&story.UpdatedAt,
-	); err != nil {
+	story, err := scanStory(runTx(ctx, tx).QueryRow(query, args...))
+	if err != nil {
 		tx.Rollback()
 		return nil, errors.New("Cannot Find Story IN DB:" + err.Error())
 	}
-
-	if titleAdded == 1 {
-		story.TitleAdded = true
-	}
-	if isFinished == 1 {
-		story.IsFinished = true
-	}
-	return &story, nil
+	return story, nil
 }
 
-// Updates Story's UpdatedAt Time in DB
-func UpdateStoryUpdateTimeTx(tx *sql.Tx, storyId int32) error {
+// Updates Story's UpdatedAt Time (and Content Version) in DB
+func UpdateStoryUpdateTimeTx(ctx context.Context, tx *sql.Tx, storyId int32) error {
 	query, args, err := sq.Update("stories").
+		Set("version", sq.Expr("version + 1")).
 		Set("updatedAt", time.Now().Format(MySQLTimeFormat)).
 		Where(sq.Eq{"id": storyId}).ToSql()
 
@@ -210,7 +294,7 @@ func UpdateStoryUpdateTimeTx(tx *sql.Tx, storyId int32) error {
 		return errors.New("Error Generating Story Update Query:" + err.Error())
 	}
 
-	if _, err = tx.Exec(query, args...); err != nil {
+	if _, err = runTx(ctx, tx).Exec(query, args...); err != nil {
 		tx.Rollback()
 		return errors.New("Error Updating Story In DB:" + err.Error())
 	}
@@ -218,86 +302,106 @@ func UpdateStoryUpdateTimeTx(tx *sql.Tx, storyId int32) error {
 	return nil
 }
 
-// Finds an Unfinished Story in DB
-func (s *MySQLStorage) GetUnfinishedStory() (*Story, error) {
-	var story Story
+// Finds the Unfinished Story of a Room in DB
+func (s *MySQLStorage) GetUnfinishedStory(ctx context.Context, room string) (*Story, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetUnfinishedStory")
+	defer span.End()
 
-	query, args, err := sq.Select("*").From("stories").
-		Where(sq.Eq{"isFinished": 0}).ToSql()
+	query, args, err := sq.Select(storyColumns...).From("stories").
+		Where(sq.Eq{"room": room}, sq.Eq{"isFinished": 0}, sq.Eq{"state": inProgressStates}).ToSql()
 
 	if err != nil {
-		s.logger.Error("Unexpected Error In Creating Query:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Unexpected Error In Creating Query")
 		return nil, errors.New("Unexpected Error In Creating Query...")
 	}
 
-	var titleAdded, isFinished int32
-	if err := s.db.QueryRow(query, args...).Scan(
-		&story.ID,
-		&story.Title,
-		&titleAdded,
-		&isFinished,
-		&story.CreatedAt,
-		&story.UpdatedAt,
-	); err != nil {
-		s.logger.Info("Cannot Find An Unfinished Story in DB:" + err.Error())
+	story, err := scanStory(s.runner(ctx).QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrStoryNotFound
+		}
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Unfinished Story From DB")
 		return nil, errors.New("Cannot Find An Unfinished Story in DB...")
 	}
+	return story, nil
+}
 
-	if titleAdded == 1 {
-		story.TitleAdded = true
-	}
+// Finds Unfinished Stories of all Rooms in DB
+func (s *MySQLStorage) GetUnfinishedStories(ctx context.Context) ([]Story, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetUnfinishedStories")
+	defer span.End()
 
-	if isFinished == 1 {
-		story.IsFinished = true
+	query := sq.Select(storyColumns...).From("stories").
+		Where(sq.Eq{"isFinished": 0}, sq.Eq{"state": inProgressStates})
+
+	rows, err := query.RunWith(s.runner(ctx)).Query()
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Getting Unfinished Stories From DB")
+		return nil, errors.New("Error Getting Unfinished Stories From DB...")
 	}
+	defer rows.Close()
 
-	return &story, nil
+	var stories []Story
+	for rows.Next() {
+		story, err := scanStory(rows)
+		if err != nil {
+			logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Story Row")
+			return nil, errors.New("Error Getting Unfinished Stories From DB...")
+		}
+		stories = append(stories, *story)
+	}
+	return stories, nil
 }
 
-// Adds words to Story's Title
-func (s *MySQLStorage) UpdateStoryTitle(storyId int32, word string) error {
-	tx, err := s.NewTransaction()
+// Adds words to Story's Title (Title is added once it has rules.TitleWords words)
+func (s *MySQLStorage) UpdateStoryTitle(ctx context.Context, storyId int32, word string, rules StoryRules) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.UpdateStoryTitle")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
 
 	if err != nil {
-		s.logger.Error(err)
+		logging.FromContext(ctx, s.logger).Error(err)
 		return err // error already formatted in newTransaction
 	}
 
 	// Check if Story exists ?
-	story, err := GetStoryTx(tx, storyId)
+	story, err := GetStoryTx(ctx, tx, storyId)
 
 	if err != nil {
-		s.logger.Error(err)
+		logging.FromContext(ctx, s.logger).Error(err)
 		return err // tx already rolledback in getStoryTx
 	}
 
-	// Check if title already contains two words
-	re, _ := regexp.Compile("^\\S+(?: \\S+)$")
-	if re.MatchString(story.Title) || story.TitleAdded {
-		s.logger.Error("Error: Story Title Is Finished Already")
+	// Check if title already contains all its words
+	if story.TitleAdded {
+		tx.Rollback()
+		logging.FromContext(ctx, s.logger).Error("Error: Story Title Is Finished Already")
 		return errors.New("Error: Story Title Is Finished Already")
+	} else if len(story.Title) == 0 && IsPunctuation(word) {
+		tx.Rollback()
+		return wrd.ErrPunctuationOnly
 	} else {
-		if len(story.Title) == 0 {
-			story.Title = word
-		} else {
-			story.Title = story.Title + " " + word
+		story.Title = AppendWord(story.Title, word)
+		if CountWords(story.Title) >= rules.TitleWords {
 			story.TitleAdded = true
+			story.State = StateWriting
 		}
 	}
 
-	if err := UpdateStoryTx(tx, *story); err != nil {
-		s.logger.Error(err)
+	if err := UpdateStoryTx(ctx, tx, *story); err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
 		return err // err already formatted in UpdateStoryTx
 	}
 
 	if err := tx.Commit(); err != nil {
-		s.logger.Error("Error executing transaction:" + err.Error())
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error executing transaction")
 		return errors.New("Errors executing transaction...")
 	}
 	return nil
 }
 
-func UpdateStoryTx(tx *sql.Tx, story Story) error {
+func UpdateStoryTx(ctx context.Context, tx *sql.Tx, story Story) error {
 	var titleAdded, isFinished int32
 	if story.TitleAdded {
 		titleAdded = 1
@@ -309,6 +413,8 @@ func UpdateStoryTx(tx *sql.Tx, story Story) error {
 		Set("title", story.Title).
 		Set("titleAdded", titleAdded).
 		Set("isFinished", isFinished).
+		Set("state", string(story.State)).
+		Set("version", sq.Expr("version + 1")).
 		Set("updatedAt", time.Now().Format(MySQLTimeFormat)).
 		Where(sq.Eq{"id": story.ID}).ToSql()
 
@@ -317,14 +423,92 @@ func UpdateStoryTx(tx *sql.Tx, story Story) error {
 		return errors.New("Error Generating Story Update Query:" + err.Error())
 	}
 
-	if _, err = tx.Exec(query, args...); err != nil {
+	if _, err = runTx(ctx, tx).Exec(query, args...); err != nil {
 		tx.Rollback()
 		return errors.New("Error Updating Story In DB:" + err.Error())
 	}
 	return nil
 }
 
-func CountFinishedParagraphsTx(tx *sql.Tx, storyId int32) (int32, error) {
+// States in which a Story can be the Unfinished Story of its Room.
+var inProgressStates = []string{string(StateCollectingTitle), string(StateWriting)}
+
+// Moves a Story to another State in DB (State machine is enforced by the services).
+// Finishing or archiving a Story also finishes its partially written Paragraph and Sentence,
+// moving it to writing finishes its Title.
+func (s *MySQLStorage) UpdateStoryState(ctx context.Context, storyId int32, state State) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.UpdateStoryState")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
+
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return err // error already formatted in newTransaction
+	}
+
+	story, err := GetStoryTx(ctx, tx, storyId)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return err // tx already rolledback in getStoryTx
+	}
+
+	if state == StateFinished || state == StateArchived {
+		if err := FinishStoryContentTx(ctx, tx, storyId); err != nil {
+			logging.FromContext(ctx, s.logger).Error(err)
+			return err // tx already rolledback in FinishStoryContentTx
+		}
+		story.IsFinished = true
+	}
+	if state == StateWriting {
+		// Title may be cut short by an admin, Words go to Sentences from now on.
+		story.TitleAdded = true
+	}
+	story.State = state
+
+	if err := UpdateStoryTx(ctx, tx, *story); err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return err // err already formatted in UpdateStoryTx
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error executing transaction")
+		return errors.New("Errors executing transaction...")
+	}
+	return nil
+}
+
+// Marks all of Story's Paragraphs and Sentences finished (Transaction)
+func FinishStoryContentTx(ctx context.Context, tx *sql.Tx, storyId int32) error {
+	query, args, err := sq.Update("sentences").Set("isFinished", 1).
+		Where(sq.Expr("paragraph IN (SELECT id FROM paragraphs WHERE story = ?)", storyId)).ToSql()
+
+	if err != nil {
+		tx.Rollback()
+		return errors.New("Error Generating Sentences Update Query:" + err.Error())
+	}
+
+	if _, err = runTx(ctx, tx).Exec(query, args...); err != nil {
+		tx.Rollback()
+		return errors.New("Error Finishing Sentences In DB:" + err.Error())
+	}
+
+	query, args, err = sq.Update("paragraphs").Set("isFinished", 1).
+		Where(sq.Eq{"story": storyId}).ToSql()
+
+	if err != nil {
+		tx.Rollback()
+		return errors.New("Error Generating Paragraphs Update Query:" + err.Error())
+	}
+
+	if _, err = runTx(ctx, tx).Exec(query, args...); err != nil {
+		tx.Rollback()
+		return errors.New("Error Finishing Paragraphs In DB:" + err.Error())
+	}
+	return nil
+}
+
+func CountFinishedParagraphsTx(ctx context.Context, tx *sql.Tx, storyId int32) (int32, error) {
 	query, args, err := sq.Select("count(*) as count").From("paragraphs").
 		Where(sq.Eq{"story": storyId}, sq.Eq{"isFinished": 1}).ToSql()
 
@@ -334,7 +518,7 @@ func CountFinishedParagraphsTx(tx *sql.Tx, storyId int32) (int32, error) {
 	}
 
 	var count int32
-	if err := tx.QueryRow(query, args...).Scan(
+	if err := runTx(ctx, tx).QueryRow(query, args...).Scan(
 		&count,
 	); err != nil {
 		tx.Rollback()
@@ -0,0 +1,86 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Keeps ctx's values (its span) but not its cancellation, so a client going away
+// cannot interrupt a Word half way through its statements.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func withoutCancel(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+// What statements run on, the DB or a transaction.
+type statementRunner interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Runs SQL statements in a span each (used with squirrel's RunWith, or directly).
+type runner struct {
+	ctx context.Context
+	db  statementRunner
+}
+
+func (s *MySQLStorage) runner(ctx context.Context) runner {
+	return runner{ctx: withoutCancel(ctx), db: s.db}
+}
+
+func runTx(ctx context.Context, tx *sql.Tx) runner {
+	return runner{ctx: withoutCancel(ctx), db: tx}
+}
+
+func (r runner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	span := r.startStatement(query)
+	defer span.End()
+	res, err := r.db.ExecContext(r.ctx, query, args...)
+	tracing.SetError(span, err)
+	return res, err
+}
+
+func (r runner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	span := r.startStatement(query)
+	defer span.End()
+	rows, err := r.db.QueryContext(r.ctx, query, args...)
+	tracing.SetError(span, err)
+	return rows, err
+}
+
+func (r runner) QueryRow(query string, args ...interface{}) *sql.Row {
+	span := r.startStatement(query)
+	defer span.End()
+	row := r.db.QueryRowContext(r.ctx, query, args...)
+	if err := row.Err(); err != sql.ErrNoRows {
+		tracing.SetError(span, err)
+	}
+	return row
+}
+
+// Span named after the statement's verb (e.g. "SQL SELECT"), with the statement (placeholders, not values).
+func (r runner) startStatement(query string) trace.Span {
+	verb := query
+	if i := strings.IndexByte(query, ' '); i > 0 {
+		verb = query[:i]
+	}
+	_, span := tracing.Start(r.ctx, "SQL "+strings.ToUpper(verb), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("db.system", "mysql"),
+		attribute.String("db.statement", query),
+	))
+	return span
+}
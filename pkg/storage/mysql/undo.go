@@ -0,0 +1,98 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/paragraph"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+)
+
+// Reverts everything a Word changed in DB (only if its Story was not changed since).
+func (s *MySQLStorage) UndoWord(ctx context.Context, undo wrd.WordUndo) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.UndoWord")
+	defer span.End()
+
+	tx, err := s.NewTransaction(ctx)
+
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return err // error already formatted in newTransaction
+	}
+
+	story, err := GetStoryTx(ctx, tx, undo.StoryID)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error(err)
+		return err // tx already rolledback in getStoryTx
+	}
+	if story.Version != undo.Version {
+		tx.Rollback()
+		return wrd.ErrUndoConflict
+	}
+
+	if undo.Story == nil {
+		// Word created the Story (it only has a Title).
+		if err := deleteTx(ctx, tx, "stories", undo.StoryID); err != nil {
+			logging.FromContext(ctx, s.logger).Error(err)
+			return err // tx already rolledback in deleteTx
+		}
+	} else {
+		if undo.NewSentence != 0 {
+			if err := deleteTx(ctx, tx, "sentences", undo.NewSentence); err != nil {
+				logging.FromContext(ctx, s.logger).Error(err)
+				return err
+			}
+		}
+		if undo.NewParagraph != 0 {
+			if err := deleteTx(ctx, tx, "paragraphs", undo.NewParagraph); err != nil {
+				logging.FromContext(ctx, s.logger).Error(err)
+				return err
+			}
+		}
+		if undo.Sentence != nil {
+			// Restores Sentence's Content (and unfinishes it).
+			if err := UpdateSentenceTx(ctx, tx, *undo.Sentence); err != nil {
+				logging.FromContext(ctx, s.logger).Error(err)
+				return err // Already rolledback in UpdateSentenceTx
+			}
+		}
+		if undo.Paragraph != 0 {
+			// Paragraph was unfinished before the Word (it may have finished it).
+			if err := UpdateParagraphTx(ctx, tx, Paragraph{ID: undo.Paragraph, Story: undo.StoryID}); err != nil {
+				logging.FromContext(ctx, s.logger).Error(err)
+				return err // tx rolledback already
+			}
+		}
+		// Restores Story's Title, TitleAdded, IsFinished and State.
+		if err := UpdateStoryTx(ctx, tx, *undo.Story); err != nil {
+			logging.FromContext(ctx, s.logger).Error(err)
+			return err // err already formatted in UpdateStoryTx
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error executing transaction")
+		return errors.New("Errors executing transaction...")
+	}
+	return nil
+}
+
+// Deletes a row (of stories, paragraphs or sentences) by ID (Transaction)
+func deleteTx(ctx context.Context, tx *sql.Tx, table string, id int32) error {
+	query, args, err := sq.Delete(table).Where(sq.Eq{"id": id}).ToSql()
+
+	if err != nil {
+		tx.Rollback()
+		return errors.New("Error Generating Delete Query:" + err.Error())
+	}
+
+	if _, err = runTx(ctx, tx).Exec(query, args...); err != nil {
+		tx.Rollback()
+		return errors.New("Error Deleting From " + table + " In DB:" + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/go-sql-driver/mysql"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+	wrd "github.com/shubhamdwivedii/collab-story/pkg/word"
+)
+
+// MySQL error number for a duplicate primary (or unique) key.
+const errDuplicateEntry = 1062
+
+var roundColumns = []string{"id", "room", "isClosed", "winner", "story", "endsAt", "createdAt"}
+
+// Scans a VoteRound selected with roundColumns (without its Candidates).
+func scanRound(row rowScanner) (*wrd.VoteRound, error) {
+	var round wrd.VoteRound
+	var isClosed int32
+	if err := row.Scan(
+		&round.ID,
+		&round.Room,
+		&isClosed,
+		&round.Winner,
+		&round.Story,
+		&round.EndsAt,
+		&round.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	round.IsClosed = isClosed == 1
+	return &round, nil
+}
+
+// Gets the open voting round (with Candidates and their votes) of a Room from DB
+func (s *MySQLStorage) GetOpenRound(ctx context.Context, room string) (*wrd.VoteRound, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetOpenRound")
+	defer span.End()
+
+	query, args, err := sq.Select(roundColumns...).From("vote_rounds").
+		Where(sq.Eq{"room": room}, sq.Eq{"isClosed": 0}).OrderBy("id DESC").Limit(1).ToSql()
+
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Unexpected Error In Creating Query")
+		return nil, errors.New("Unexpected Error In Creating Query...")
+	}
+	return s.getRound(ctx, query, args)
+}
+
+// Gets a voting round (with Candidates and their votes) from DB
+func (s *MySQLStorage) GetRound(ctx context.Context, roundId int32) (*wrd.VoteRound, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetRound")
+	defer span.End()
+
+	query, args, err := sq.Select(roundColumns...).From("vote_rounds").Where(sq.Eq{"id": roundId}).ToSql()
+
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Unexpected Error In Creating Query")
+		return nil, errors.New("Unexpected Error In Creating Query...")
+	}
+	return s.getRound(ctx, query, args)
+}
+
+func (s *MySQLStorage) getRound(ctx context.Context, query string, args []interface{}) (*wrd.VoteRound, error) {
+	round, err := scanRound(s.runner(ctx).QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, wrd.ErrRoundNotFound
+		}
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Voting Round From DB")
+		return nil, errors.New("Error Reading Voting Round From DB...")
+	}
+
+	candidates := sq.Select("c.id", "c.round", "c.word", "count(v.voter)", "c.createdAt").From("vote_candidates c").
+		LeftJoin("votes v ON v.candidate = c.id").
+		Where(sq.Eq{"c.round": round.ID}).GroupBy("c.id").OrderBy("c.id")
+
+	rows, err := candidates.RunWith(s.runner(ctx)).Query()
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Candidates From DB")
+		return nil, errors.New("Error Reading Candidates From DB...")
+	}
+	defer rows.Close()
+
+	round.Candidates = []wrd.Candidate{}
+	for rows.Next() {
+		var candidate wrd.Candidate
+		if err := rows.Scan(
+			&candidate.ID,
+			&candidate.Round,
+			&candidate.Word,
+			&candidate.Votes,
+			&candidate.CreatedAt,
+		); err != nil {
+			logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Candidates From DB")
+			return nil, errors.New("Error Reading Candidates From DB...")
+		}
+		round.Candidates = append(round.Candidates, candidate)
+	}
+	return round, rows.Err()
+}
+
+// Gets open voting rounds (of all Rooms, without Candidates) that ended before now from DB
+func (s *MySQLStorage) GetDueRounds(ctx context.Context, now time.Time) ([]wrd.VoteRound, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.GetDueRounds")
+	defer span.End()
+
+	query := sq.Select(roundColumns...).From("vote_rounds").
+		Where(sq.Eq{"isClosed": 0}, sq.LtOrEq{"endsAt": now.Format(MySQLTimeFormat)}).OrderBy("id")
+
+	rows, err := query.RunWith(s.runner(ctx)).Query()
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Getting Due Voting Rounds From DB")
+		return nil, errors.New("Error Getting Due Voting Rounds From DB...")
+	}
+	defer rows.Close()
+
+	var rounds []wrd.VoteRound
+	for rows.Next() {
+		round, err := scanRound(rows)
+		if err != nil {
+			logging.FromContext(ctx, s.logger).WithError(err).Error("Error Reading Voting Round Row")
+			return nil, errors.New("Error Getting Due Voting Rounds From DB...")
+		}
+		rounds = append(rounds, *round)
+	}
+	return rounds, nil
+}
+
+// Adds a new voting round (of a Room) to DB
+func (s *MySQLStorage) AddRound(ctx context.Context, room string, endsAt time.Time) (int32, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.AddRound")
+	defer span.End()
+
+	query := sq.Insert("vote_rounds").Columns("room", "endsAt").Values(room, endsAt.Format(MySQLTimeFormat))
+
+	res, err := query.RunWith(s.runner(ctx)).Exec()
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Adding Voting Round To DB")
+		return 0, errors.New("Error Adding Voting Round To DB...")
+	}
+	id, _ := res.LastInsertId()
+	return int32(id), nil
+}
+
+// Adds a candidate Word (to a voting round) to DB
+func (s *MySQLStorage) AddCandidate(ctx context.Context, roundId int32, word string) (int32, error) {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.AddCandidate")
+	defer span.End()
+
+	query := sq.Insert("vote_candidates").Columns("round", "word").Values(roundId, word)
+
+	res, err := query.RunWith(s.runner(ctx)).Exec()
+	if err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Adding Candidate To DB")
+		return 0, errors.New("Error Adding Candidate To DB...")
+	}
+	id, _ := res.LastInsertId()
+	return int32(id), nil
+}
+
+// Adds a vote (one per voter per round) to DB
+func (s *MySQLStorage) AddVote(ctx context.Context, roundId int32, candidateId int32, voter string) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.AddVote")
+	defer span.End()
+
+	query := sq.Insert("votes").Columns("round", "candidate", "voter").Values(roundId, candidateId, voter)
+
+	if _, err := query.RunWith(s.runner(ctx)).Exec(); err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == errDuplicateEntry {
+			return wrd.ErrAlreadyVoted
+		}
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Adding Vote To DB")
+		return errors.New("Error Adding Vote To DB...")
+	}
+	return nil
+}
+
+// Closes a voting round (with its winning Candidate, if any) in DB
+func (s *MySQLStorage) CloseRound(ctx context.Context, roundId int32, winner int32, storyId int32) error {
+	ctx, span := tracing.Start(ctx, "MySQLStorage.CloseRound")
+	defer span.End()
+
+	query := sq.Update("vote_rounds").Set("isClosed", 1).Set("winner", winner).Set("story", storyId).
+		Where(sq.Eq{"id": roundId})
+
+	if _, err := query.RunWith(s.runner(ctx)).Exec(); err != nil {
+		logging.FromContext(ctx, s.logger).WithError(err).Error("Error Closing Voting Round In DB")
+		return errors.New("Error Closing Voting Round In DB...")
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package story
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/cache"
+	log "github.com/sirupsen/logrus"
+)
+
+// A StoryStorage that reads through a Cache. Stories no longer in progress are cached indefinitely,
+// in-progress Stories and listings for ttl (or until invalidated by InvalidateStory).
+type CachingStoryStorage struct {
+	// Current generation of cached Story listings, part of their keys (bumped to invalidate all pages).
+	// Kept out of the Cache, where it could be evicted and start over at a generation whose pages are still cached.
+	// Accessed atomically (first field, so it is 64-bit aligned).
+	listingGeneration int64
+	storage           StoryStorage
+	cache             cache.Cache
+	ttl               time.Duration
+	logger            *log.Logger
+}
+
+func NewCachingStoryStorage(storage StoryStorage, cache cache.Cache, ttl time.Duration, logger *log.Logger) *CachingStoryStorage {
+	cs := new(CachingStoryStorage)
+	cs.storage = storage
+	cs.cache = cache
+	cs.ttl = ttl
+	cs.logger = logger
+	return cs
+}
+
+func (cs *CachingStoryStorage) GetStory(ctx context.Context, storyId int32) (*Story, error) {
+	key := fmt.Sprintf("story:%d", storyId)
+	var story Story
+	if cs.load(key, &story) {
+		return &story, nil
+	}
+
+	res, err := cs.storage.GetStory(ctx, storyId)
+	if err != nil {
+		return nil, err
+	}
+	cs.store(key, res, !res.State.InProgress())
+	return res, nil
+}
+
+func (cs *CachingStoryStorage) GetAllStories(ctx context.Context, filter StoryFilter) (*StoriesResponse, error) {
+	key := fmt.Sprintf("stories:%d:%d:%d:%v:%s", atomic.LoadInt64(&cs.listingGeneration), filter.Limit, filter.Offset, filter.States, filter.Room)
+	var storiesRes StoriesResponse
+	if cs.load(key, &storiesRes) {
+		return &storiesRes, nil
+	}
+
+	res, err := cs.storage.GetAllStories(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	cs.store(key, res, false)
+	return res, nil
+}
+
+func (cs *CachingStoryStorage) GetStoryDetail(ctx context.Context, storyId int32) (*StoryResponse, error) {
+	key := fmt.Sprintf("story-detail:%d", storyId)
+	var storyRes StoryResponse
+	if cs.load(key, &storyRes) {
+		return &storyRes, nil
+	}
+
+	res, err := cs.storage.GetStoryDetail(ctx, storyId)
+	if err != nil {
+		return nil, err
+	}
+	cs.store(key, res, !res.State.InProgress())
+	return res, nil
+}
+
+func (cs *CachingStoryStorage) GetForks(ctx context.Context, storyId int32) ([]StoryBrief, error) {
+	return cs.storage.GetForks(ctx, storyId)
+}
+
+func (cs *CachingStoryStorage) GetRoom(ctx context.Context, name string) (*Room, error) {
+	return cs.storage.GetRoom(ctx, name)
+}
+
+func (cs *CachingStoryStorage) SaveRoom(ctx context.Context, room Room) error {
+	return cs.storage.SaveRoom(ctx, room)
+}
+
+// Removes a (changed) Story from Cache, along with all cached listings.
+func (cs *CachingStoryStorage) InvalidateStory(storyId int32) {
+	cs.cache.Delete(fmt.Sprintf("story:%d", storyId), fmt.Sprintf("story-detail:%d", storyId))
+	// Old listing pages are never read again, they expire (or get evicted) on their own.
+	atomic.AddInt64(&cs.listingGeneration, 1)
+}
+
+func (cs *CachingStoryStorage) load(key string, dest interface{}) bool {
+	value, ok := cs.cache.Get(key)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(value, dest); err != nil {
+		cs.logger.WithError(err).Error("Error Unmarshalling Cached Value")
+		cs.cache.Delete(key)
+		return false
+	}
+	return true
+}
+
+func (cs *CachingStoryStorage) store(key string, value interface{}, final bool) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		cs.logger.WithError(err).Error("Error Marshalling Value For Cache")
+		return
+	}
+	ttl := cs.ttl
+	if final {
+		ttl = 0 // Stories no longer in progress don't change (unless invalidated).
+	}
+	cs.cache.Set(key, data, ttl)
+}
@@ -0,0 +1,90 @@
+package story
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/cache"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Counts how many times Stories were loaded from "DB".
+type countingStorage struct {
+	state State
+	loads int
+}
+
+func (s *countingStorage) GetStory(ctx context.Context, storyId int32) (*Story, error) {
+	s.loads++
+	return &Story{ID: storyId, State: s.state}, nil
+}
+
+func (s *countingStorage) GetAllStories(ctx context.Context, filter StoryFilter) (*StoriesResponse, error) {
+	s.loads++
+	return &StoriesResponse{Limit: filter.Limit, Offset: filter.Offset}, nil
+}
+
+func (s *countingStorage) GetStoryDetail(ctx context.Context, storyId int32) (*StoryResponse, error) {
+	s.loads++
+	return &StoryResponse{ID: storyId, State: s.state}, nil
+}
+
+func (s *countingStorage) GetForks(ctx context.Context, storyId int32) ([]StoryBrief, error) {
+	return nil, nil
+}
+
+func (s *countingStorage) GetRoom(ctx context.Context, name string) (*Room, error) {
+	return nil, ErrRoomNotFound
+}
+
+func (s *countingStorage) SaveRoom(ctx context.Context, room Room) error {
+	return nil
+}
+
+func TestCachingStoryStorage(t *testing.T) {
+	storage := &countingStorage{state: StateWriting}
+	cs := NewCachingStoryStorage(storage, cache.NewLRUCache(10), time.Minute, log.New())
+
+	for i := 0; i < 3; i++ {
+		storyRes, err := cs.GetStoryDetail(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), storyRes.ID)
+		_, err = cs.GetAllStories(context.Background(), StoryFilter{Limit: 10})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, storage.loads, "Expected Repeated Reads To Be Cached")
+
+	cs.InvalidateStory(1)
+	_, err := cs.GetStoryDetail(context.Background(), 1)
+	require.NoError(t, err)
+	_, err = cs.GetAllStories(context.Background(), StoryFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 4, storage.loads, "Expected Invalidated Story And Listing To Be Reloaded")
+}
+
+func TestCachingStoryStorageListingsStayInvalidated(t *testing.T) {
+	storage := &countingStorage{state: StateWriting}
+	cs := NewCachingStoryStorage(storage, cache.NewLRUCache(3), time.Minute, log.New())
+	listing := func() {
+		_, err := cs.GetAllStories(context.Background(), StoryFilter{Limit: 10})
+		require.NoError(t, err)
+	}
+
+	listing()
+	cs.InvalidateStory(1)
+	listing()
+	listing()
+	assert.Equal(t, 2, storage.loads)
+
+	// Evict everything but the current listing page.
+	for _, storyId := range []int32{5, 6} {
+		_, err := cs.GetStory(context.Background(), storyId)
+		require.NoError(t, err)
+	}
+	cs.InvalidateStory(5)
+	listing()
+	assert.Equal(t, 5, storage.loads, "Expected Invalidated Listing Not To Be Served Again")
+}
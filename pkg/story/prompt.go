@@ -0,0 +1,66 @@
+package story
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidPrompt = errors.New("Invalid Prompt")
+
+// A prompt or theme a new Story is seeded with (e.g. "space pirates"),
+// with keywords the theme filter (if enabled) requires in the Title.
+type Prompt struct {
+	Text     string   `json:"text"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// Prompt must fit in DB columns (prompt is 256 chars, keywords 512 chars).
+func (p Prompt) Validate() error {
+	if utf8.RuneCountInString(p.Text) > 256 || len(p.Keywords) > 20 ||
+		utf8.RuneCountInString(strings.Join(p.Keywords, ",")) > 512 {
+		return ErrInvalidPrompt
+	}
+	for _, keyword := range p.Keywords {
+		if keyword == "" || strings.ContainsAny(keyword, ", ") {
+			return ErrInvalidPrompt
+		}
+	}
+	return nil
+}
+
+// Reads a prompts file, one prompt per line with optional keywords after a "|"
+// (e.g. "space pirates | ship, treasure, captain"). Blank lines and lines starting with # are skipped.
+func LoadPrompts(path string) ([]Prompt, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var prompts []Prompt
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "|", 2)
+		prompt := Prompt{Text: strings.TrimSpace(parts[0])}
+		if len(parts) == 2 {
+			for _, keyword := range strings.Split(parts[1], ",") {
+				if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+					prompt.Keywords = append(prompt.Keywords, keyword)
+				}
+			}
+		}
+		if err := prompt.Validate(); err != nil {
+			return nil, errors.New("Invalid Prompt: " + line)
+		}
+		prompts = append(prompts, prompt)
+	}
+	return prompts, scanner.Err()
+}
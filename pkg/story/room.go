@@ -0,0 +1,77 @@
+package story
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+)
+
+// Room used by POST /add (and by Stories created before Rooms existed).
+const DefaultRoom = "default"
+
+var (
+	ErrRoomNotFound    = errors.New("Room Not Found")
+	ErrInvalidRoomName = errors.New("Invalid Room Name")
+	ErrInvalidRules    = errors.New("Invalid Story Rules")
+)
+
+var roomNameRegex = regexp.MustCompile("^[a-z0-9][a-z0-9_-]{0,31}$")
+
+// How long each part of a Story is.
+type StoryRules struct {
+	TitleWords    int32 `json:"title_words" yaml:"title_words"`
+	SentenceWords int32 `json:"sentence_words" yaml:"sentence_words"` // Hard maximum.
+	// A Word ending with ".", "!" or "?" ends a Sentence early once it has this many Words (0, the default, disables).
+	MinSentenceWords   int32 `json:"min_sentence_words" yaml:"min_sentence_words"`
+	ParagraphSentences int32 `json:"paragraph_sentences" yaml:"paragraph_sentences"`
+	StoryParagraphs    int32 `json:"story_paragraphs" yaml:"story_paragraphs"`
+	// Seconds a voting round on candidate Words lasts, 0 adds Words right away (first come, first served).
+	VoteSeconds int32 `json:"vote_seconds" yaml:"vote_seconds"`
+}
+
+var DefaultStoryRules = StoryRules{
+	TitleWords:         2,
+	SentenceWords:      15,
+	MinSentenceWords:   0, // Rooms (or config) opt in to ending Sentences early.
+	ParagraphSentences: 10,
+	StoryParagraphs:    7,
+}
+
+// Rules must fit in DB columns (title is 256 chars, sentence is 2048 chars, with up to 36 chars per word).
+func (r StoryRules) Validate() error {
+	if r.TitleWords < 1 || r.TitleWords > 6 ||
+		r.SentenceWords < 2 || r.SentenceWords > 50 ||
+		r.MinSentenceWords < 0 || r.MinSentenceWords == 1 || r.MinSentenceWords > r.SentenceWords ||
+		r.ParagraphSentences < 1 || r.ParagraphSentences > 100 ||
+		r.StoryParagraphs < 1 || r.StoryParagraphs > 100 ||
+		r.VoteSeconds < 0 || r.VoteSeconds > 86400 {
+		return ErrInvalidRules
+	}
+	return nil
+}
+
+// Is a Sentence with content finished ? (full, or ended with punctuation after enough words)
+func (r StoryRules) SentenceFinished(content string) bool {
+	words := strings.Fields(content)
+	count := int32(len(words))
+	if count >= r.SentenceWords {
+		return true
+	}
+	return r.MinSentenceWords > 0 && count >= r.MinSentenceWords && EndsSentence(words[count-1])
+}
+
+// A named channel with its own active Story (and optionally its own StoryRules).
+type Room struct {
+	Name   string     `json:"name"`
+	Rules  StoryRules `json:"rules"`
+	Prompt *Prompt    `json:"prompt,omitempty"` // Chosen by an admin for the Room's next Story (used once).
+}
+
+func ValidateRoomName(name string) error {
+	if !roomNameRegex.MatchString(name) {
+		return ErrInvalidRoomName
+	}
+	return nil
+}
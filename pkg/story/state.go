@@ -0,0 +1,58 @@
+package story
+
+import "errors"
+
+// Lifecycle State of a Story.
+type State string
+
+const (
+	StateCollectingTitle State = "collecting_title" // Words are added to Title.
+	StateWriting         State = "writing"          // Words are added to Sentences.
+	StateFinished        State = "finished"         // No more Words can be added.
+	StateArchived        State = "archived"         // Finished (or abandoned), only listed on request.
+	StateHidden          State = "hidden"           // Never shown publicly (e.g. offensive).
+)
+
+var (
+	ErrStoryNotFound     = errors.New("Story Not Found")
+	ErrInvalidState      = errors.New("Invalid Story State")
+	ErrInvalidTransition = errors.New("Story Cannot Transition To This State")
+)
+
+// States a Story can move to from each State.
+var transitions = map[State][]State{
+	StateCollectingTitle: {StateWriting, StateArchived, StateHidden},
+	StateWriting:         {StateFinished, StateArchived, StateHidden},
+	StateFinished:        {StateArchived, StateHidden},
+	StateArchived:        {StateFinished, StateHidden},
+	StateHidden:          {StateFinished, StateArchived},
+}
+
+var AllStates = []State{StateCollectingTitle, StateWriting, StateFinished, StateArchived, StateHidden}
+
+// States listed publicly when no State is requested.
+var DefaultListedStates = []State{StateCollectingTitle, StateWriting, StateFinished}
+
+func (s State) Valid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// Hidden Stories are only visible to admins.
+func (s State) Visible() bool {
+	return s != StateHidden
+}
+
+// Words can only be added to Stories in these States.
+func (s State) InProgress() bool {
+	return s == StateCollectingTitle || s == StateWriting
+}
+
+func CanTransition(from State, to State) bool {
+	for _, state := range transitions[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
@@ -1,27 +1,48 @@
 package story
 
 import (
+	"context"
 	"time"
 
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
 	log "github.com/sirupsen/logrus"
 )
 
 type Story struct {
 	ID         int32     `json:"id"`
+	Room       string    `json:"room"`
 	Title      string    `json:"title"`
 	TitleAdded bool      `json:"title_added"`
 	IsFinished bool      `json:"is_finished"`
+	State      State     `json:"state"`
+	Version    int32     `json:"version"`
+	Parent     int32     `json:"parent,omitempty"` // Story this one was forked from.
+	Prompt     string    `json:"prompt,omitempty"`
+	Keywords   []string  `json:"keywords,omitempty"` // of the Prompt's theme
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 type StoryBrief struct {
 	ID        int32     `json:"id"`
+	Room      string    `json:"room"`
 	Title     string    `json:"title"`
+	State     State     `json:"state"`
+	Version   int32     `json:"version"`
+	Parent    int32     `json:"parent,omitempty"`
+	Prompt    string    `json:"prompt,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Which Stories to list.
+type StoryFilter struct {
+	Limit  int32
+	Offset int32
+	States []State // Stories in any of these States.
+	Room   string  // Stories in this Room (all Rooms if empty).
+}
+
 type StoriesResponse struct {
 	Limit   int32        `json:"limit"`
 	Offset  int32        `json:"offset"`
@@ -31,7 +52,15 @@ type StoriesResponse struct {
 
 type StoryResponse struct {
 	ID         int32            `json:"id"`
+	Room       string           `json:"room"`
 	Title      string           `json:"title"`
+	State      State            `json:"state"`
+	Version    int32            `json:"version"`
+	IsFinished bool             `json:"is_finished"`
+	Parent     int32            `json:"parent,omitempty"`
+	Lineage    []int32          `json:"lineage,omitempty"` // Stories this one descends from, parent first.
+	Prompt     string           `json:"prompt,omitempty"`
+	Keywords   []string         `json:"keywords,omitempty"`
 	CreatedAt  time.Time        `json:"created_at"`
 	UpdatedAt  time.Time        `json:"updated_at"`
 	Paragraphs []ParagraphBrief `json:"paragraphs"`
@@ -43,29 +72,97 @@ type ParagraphBrief struct {
 }
 
 type StoryStorage interface {
-	GetAllStories(limit int32, offset int32) (*StoriesResponse, error)
-	GetStoryDetail(storyId int32) (*StoryResponse, error)
+	GetStory(ctx context.Context, storyId int32) (*Story, error)
+	GetAllStories(ctx context.Context, filter StoryFilter) (*StoriesResponse, error)
+	GetStoryDetail(ctx context.Context, storyId int32) (*StoryResponse, error)
+	// Stories forked from a Story.
+	GetForks(ctx context.Context, storyId int32) ([]StoryBrief, error)
+
+	GetRoom(ctx context.Context, name string) (*Room, error)
+	SaveRoom(ctx context.Context, room Room) error
 }
 
 type StoryService struct {
-	storage StoryStorage
-	logger  *log.Logger
+	storage      StoryStorage
+	defaultRules StoryRules // of Rooms that were never configured
+	logger       *log.Logger
 	// sync.Mutex // not needed for read only operation (tx implemented in sql)
 }
 
 func NewStoryService(storage StoryStorage, logger *log.Logger) *StoryService {
 	strsrv := new(StoryService)
 	strsrv.storage = storage
+	strsrv.defaultRules = DefaultStoryRules
 	strsrv.logger = logger
 	return strsrv
 }
 
-func (srv *StoryService) GetAllStories(limit int32, offset int32) (*StoriesResponse, error) {
-	// Add Some Metrics Here ? Or Some Business Logic
-	return srv.storage.GetAllStories(limit, offset)
+// Sets the StoryRules of Rooms that were never configured (DefaultStoryRules unless set).
+func (srv *StoryService) SetDefaultRules(rules StoryRules) {
+	srv.defaultRules = rules
+}
+
+// Gets a Story without its Paragraphs (cheap, used for conditional requests).
+func (srv *StoryService) GetStory(ctx context.Context, storyId int32) (*Story, error) {
+	return srv.storage.GetStory(ctx, storyId)
+}
+
+func (srv *StoryService) GetAllStories(ctx context.Context, filter StoryFilter) (*StoriesResponse, error) {
+	if len(filter.States) == 0 {
+		filter.States = DefaultListedStates
+	}
+	return srv.storage.GetAllStories(ctx, filter)
 }
 
-func (srv *StoryService) GetStoryDetail(storyId int32) (*StoryResponse, error) {
+func (srv *StoryService) GetStoryDetail(ctx context.Context, storyId int32) (*StoryResponse, error) {
 	// Add Some Metrics Here ? Or Some Business Logic
-	return srv.storage.GetStoryDetail(storyId)
+	return srv.storage.GetStoryDetail(ctx, storyId)
+}
+
+// Lists (visible) Stories forked from a Story.
+func (srv *StoryService) GetForks(ctx context.Context, storyId int32) ([]StoryBrief, error) {
+	story, err := srv.storage.GetStory(ctx, storyId)
+	if err != nil || !story.State.Visible() {
+		return nil, ErrStoryNotFound
+	}
+
+	forks, err := srv.storage.GetForks(ctx, storyId)
+	if err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Get Forks.")
+		return nil, err
+	}
+	visible := []StoryBrief{}
+	for _, fork := range forks {
+		if fork.State.Visible() {
+			visible = append(visible, fork)
+		}
+	}
+	return visible, nil
+}
+
+// Gets a Room (with default StoryRules if Room was never configured).
+func (srv *StoryService) GetRoom(ctx context.Context, name string) (*Room, error) {
+	if err := ValidateRoomName(name); err != nil {
+		return nil, err
+	}
+
+	room, err := srv.storage.GetRoom(ctx, name)
+	if err == ErrRoomNotFound {
+		return &Room{Name: name, Rules: srv.defaultRules}, nil
+	} else if err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Get Room.")
+		return nil, err
+	}
+	return room, nil
+}
+
+// Creates or updates a Room's StoryRules (applies to Words added from now on).
+func (srv *StoryService) SaveRoom(ctx context.Context, room Room) error {
+	if err := ValidateRoomName(room.Name); err != nil {
+		return err
+	}
+	if err := room.Rules.Validate(); err != nil {
+		return err
+	}
+	return srv.storage.SaveRoom(ctx, room)
 }
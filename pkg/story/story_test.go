@@ -0,0 +1,37 @@
+package story
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomValidation(t *testing.T) {
+	require.NoError(t, ValidateRoomName(DefaultRoom))
+	require.NoError(t, ValidateRoomName("space-pirates_2"))
+	require.Error(t, ValidateRoomName(""))
+	require.Error(t, ValidateRoomName("Space Pirates"))
+	require.Error(t, ValidateRoomName("a-very-long-room-name-that-goes-on-and-on"))
+
+	require.NoError(t, DefaultStoryRules.Validate())
+	rules := DefaultStoryRules
+	rules.SentenceWords = 1
+	require.Equal(t, ErrInvalidRules, rules.Validate())
+
+	rules = DefaultStoryRules
+	rules.VoteSeconds = 30
+	require.NoError(t, rules.Validate())
+	rules.VoteSeconds = -1
+	require.Equal(t, ErrInvalidRules, rules.Validate())
+}
+
+func TestSentenceFinished(t *testing.T) {
+	assert.False(t, DefaultStoryRules.SentenceFinished("once upon a time."), "Expected Punctuation Not To End Sentences By Default")
+
+	rules := DefaultStoryRules
+	rules.MinSentenceWords = 3
+	assert.False(t, rules.SentenceFinished("once upon."))
+	assert.True(t, rules.SentenceFinished("once upon a time."))
+	assert.True(t, rules.SentenceFinished("one two three four five six seven eight nine ten eleven twelve thirteen fourteen fifteen"))
+}
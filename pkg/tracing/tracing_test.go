@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestChildSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ctx, root := provider.Tracer(Instrumentation).Start(context.Background(), "POST /add", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := Start(ctx, "WordService.AddWord")
+	SetError(child, nil)
+	SetError(child, errors.New("Error Adding Word"))
+	child.End()
+	root.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "WordService.AddWord", spans[0].Name)
+	assert.Equal(t, root.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, root.SpanContext().TraceID(), spans[0].SpanContext.TraceID())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+
+	// Untraced code gets no-op spans, rather than starting traces of its own.
+	ctx, span := Start(context.Background(), "untraced")
+	assert.False(t, span.IsRecording())
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+	SetError(span, errors.New("ignored"))
+	span.End()
+	assert.Len(t, exporter.GetSpans(), 2)
+}
@@ -0,0 +1,223 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+)
+
+// Content of a redacted Sentence.
+const RedactedSentence = "[redacted]"
+
+var (
+	ErrSentenceNotFound   = errors.New("Sentence Not Found")
+	ErrParagraphNotFound  = errors.New("Paragraph Not Found")
+	ErrInvalidWordIndex   = errors.New("Invalid Word Index")
+	ErrEmptySentence      = errors.New("Sentence Cannot Be Empty, Redact It Instead")
+	ErrPunctuationOnly    = errors.New("Punctuation Must Follow A Word")
+	ErrInvalidTitleLength = errors.New("Invalid Title Length")
+)
+
+// Records every moderator change (edits of Words, Sentences and Titles) in storage.
+func (srv *WordService) EnableAudit(storage moderation.AuditStorage) {
+	srv.audit = storage
+}
+
+// Moderators' changes (latest first).
+func (srv *WordService) GetAuditEntries(ctx context.Context, limit int32, offset int32) ([]moderation.AuditEntry, error) {
+	if srv.audit == nil {
+		return []moderation.AuditEntry{}, nil
+	}
+	return srv.audit.GetAuditEntries(ctx, limit, offset)
+}
+
+// All Sentences (with their IDs) of a Story, so moderators can find the one to edit.
+func (srv *WordService) GetStorySentences(ctx context.Context, storyId int32) ([]Sentence, error) {
+	if _, err := srv.storage.GetStory(ctx, storyId); err != nil {
+		return nil, ErrStoryNotFound
+	}
+	return srv.storage.GetStorySentences(ctx, storyId)
+}
+
+// Replaces the Word at index (0 based, punctuation belongs to the Word before it) of a Sentence.
+func (srv *WordService) ReplaceWord(ctx context.Context, sentenceId int32, index int, word string, actor string) (*Sentence, error) {
+	if err := ValidateWord(word); err != nil {
+		return nil, err
+	}
+	if IsPunctuation(word) {
+		return nil, ErrPunctuationOnly
+	}
+	word = NormalizeWord(word)
+
+	return srv.editSentence(ctx, sentenceId, actor, moderation.ActionReplaceWord, false, func(words []string) ([]string, error) {
+		if index < 0 || index >= len(words) {
+			return nil, ErrInvalidWordIndex
+		}
+		words[index] = word
+		return words, nil
+	})
+}
+
+// Deletes the Word at index (0 based, along with its punctuation) of a Sentence.
+func (srv *WordService) DeleteWord(ctx context.Context, sentenceId int32, index int, actor string) (*Sentence, error) {
+	return srv.editSentence(ctx, sentenceId, actor, moderation.ActionDeleteWord, false, func(words []string) ([]string, error) {
+		if index < 0 || index >= len(words) {
+			return nil, ErrInvalidWordIndex
+		}
+		if len(words) == 1 {
+			return nil, ErrEmptySentence
+		}
+		return append(words[:index], words[index+1:]...), nil
+	})
+}
+
+// Replaces a whole Sentence with RedactedSentence, the Sentence is finished (if it was not).
+func (srv *WordService) RedactSentence(ctx context.Context, sentenceId int32, actor string) (*Sentence, error) {
+	return srv.editSentence(ctx, sentenceId, actor, moderation.ActionRedactSentence, true, func(words []string) ([]string, error) {
+		return []string{RedactedSentence}, nil
+	})
+}
+
+// Edits a Sentence's Words under the lock of its Story's Room, and records the change.
+// Only the Story's current Sentence can be finished (or reopened, e.g. by a deleted Word) by an edit,
+// as per its Room's StoryRules.
+func (srv *WordService) editSentence(ctx context.Context, sentenceId int32, actor string, action string, finish bool, edit func([]string) ([]string, error)) (*Sentence, error) {
+	sentence, err := srv.storage.GetSentence(ctx, sentenceId)
+	if err != nil {
+		return nil, ErrSentenceNotFound
+	}
+	paragraph, err := srv.storage.GetParagraph(ctx, sentence.Paragraph)
+	if err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Unexpected Error: Paragraph Missing")
+		return nil, err
+	}
+	story, err := srv.storage.GetStory(ctx, paragraph.Story)
+	if err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Unexpected Error: Story Missing")
+		return nil, err
+	}
+
+	lock := srv.roomLock(story.Room)
+	defer lock.Unlock()
+	// A Word being added to this Sentence must not interleave with editing it.
+	srv.lockRoom(ctx, lock)
+
+	rm, err := srv.getRoom(ctx, story.Room)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sentence may have changed while waiting for the lock.
+	sentence, err = srv.storage.GetSentence(ctx, sentenceId)
+	if err != nil {
+		return nil, ErrSentenceNotFound
+	}
+	before := sentence.Content
+
+	words, err := edit(strings.Fields(sentence.Content))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := srv.storage.EditSentence(ctx, sentenceId, strings.Join(words, " "), finish, rm.Rules); err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Edit Sentence.")
+		return nil, err
+	}
+	for _, invalidator := range srv.invalidators {
+		invalidator.InvalidateStory(story.ID)
+	}
+
+	sentence, err = srv.storage.GetSentence(ctx, sentenceId)
+	if err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Unexpected Error: Sentence Missing")
+		return nil, err
+	}
+	srv.recordAudit(ctx, moderation.AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		StoryID:    story.ID,
+		SentenceID: sentence.ID,
+		Before:     before,
+		After:      sentence.Content,
+	})
+	return sentence, nil
+}
+
+// Replaces a Story's Title, with at most as many Words as its Room's StoryRules allow.
+// A Title still being collected is finished if the new one has enough Words.
+func (srv *WordService) RenameStory(ctx context.Context, storyId int32, title string, actor string) (*Story, error) {
+	words := strings.Fields(title)
+	for i, word := range words {
+		if err := ValidateWord(word); err != nil {
+			return nil, err
+		}
+		if i == 0 && IsPunctuation(word) {
+			return nil, ErrPunctuationOnly
+		}
+		words[i] = NormalizeWord(word)
+	}
+
+	story, err := srv.storage.GetStory(ctx, storyId)
+	if err != nil {
+		return nil, ErrStoryNotFound
+	}
+
+	lock := srv.roomLock(story.Room)
+	defer lock.Unlock()
+	// A Word being added to this Story's Title must not interleave with renaming it.
+	srv.lockRoom(ctx, lock)
+
+	rm, err := srv.getRoom(ctx, story.Room)
+	if err != nil {
+		return nil, err
+	}
+	title = strings.Join(words, " ")
+	if count := CountWords(title); count < 1 || count > rm.Rules.TitleWords {
+		return nil, ErrInvalidTitleLength
+	}
+
+	story, err = srv.storage.GetStory(ctx, storyId)
+	if err != nil {
+		return nil, ErrStoryNotFound
+	}
+	before := story.Title
+
+	if err := srv.storage.RenameStory(ctx, storyId, title, rm.Rules); err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Rename Story.")
+		return nil, err
+	}
+	for _, invalidator := range srv.invalidators {
+		invalidator.InvalidateStory(storyId)
+	}
+
+	story, err = srv.storage.GetStory(ctx, storyId)
+	if err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Unexpected Error: Story Missing")
+		return nil, err
+	}
+	srv.recordAudit(ctx, moderation.AuditEntry{
+		Actor:   actor,
+		Action:  moderation.ActionRenameStory,
+		StoryID: storyId,
+		Before:  before,
+		After:   story.Title,
+	})
+	return story, nil
+}
+
+func (srv *WordService) recordAudit(ctx context.Context, entry moderation.AuditEntry) {
+	if srv.audit == nil {
+		return
+	}
+	entry.CreatedAt = time.Now()
+	if err := srv.audit.AddAuditEntry(ctx, entry); err != nil {
+		// Change is already made, a failure here only means it is missing from the audit trail.
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Record Audit Entry")
+	}
+}
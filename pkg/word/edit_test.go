@@ -0,0 +1,119 @@
+package word
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
+	. "github.com/shubhamdwivedii/collab-story/pkg/paragraph"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAuditStorage struct {
+	entries []moderation.AuditEntry
+}
+
+func (s *recordingAuditStorage) AddAuditEntry(ctx context.Context, entry moderation.AuditEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *recordingAuditStorage) GetAuditEntries(ctx context.Context, limit int32, offset int32) ([]moderation.AuditEntry, error) {
+	return s.entries, nil
+}
+
+func TestEditSentence(t *testing.T) {
+	storage := writingStoryStorage()
+	storage.SaveSentence(Sentence{ID: 3, Paragraph: 2, Content: "the darn dragon slept"})
+	audit := new(recordingAuditStorage)
+	srv := NewWordService(storage, log.New())
+	srv.EnableAudit(audit)
+
+	sentence, err := srv.ReplaceWord(context.Background(), 3, 1, "old", "mod")
+	require.NoError(t, err)
+	assert.Equal(t, "the old dragon slept", sentence.Content)
+
+	sentence, err = srv.DeleteWord(context.Background(), 3, 1, "mod")
+	require.NoError(t, err)
+	assert.Equal(t, "the dragon slept", sentence.Content)
+	assert.False(t, sentence.IsFinished)
+
+	_, err = srv.ReplaceWord(context.Background(), 3, 3, "word", "mod")
+	assert.Equal(t, ErrInvalidWordIndex, err)
+	_, err = srv.ReplaceWord(context.Background(), 3, 0, "!", "mod")
+	assert.Equal(t, ErrPunctuationOnly, err)
+	_, err = srv.DeleteWord(context.Background(), 4, 0, "mod")
+	assert.Equal(t, ErrSentenceNotFound, err)
+
+	sentence, err = srv.RedactSentence(context.Background(), 3, "mod")
+	require.NoError(t, err)
+	assert.Equal(t, RedactedSentence, sentence.Content)
+	assert.True(t, sentence.IsFinished, "Expected Redacted Sentence To Be Finished")
+
+	_, err = srv.DeleteWord(context.Background(), 3, 0, "mod")
+	assert.Equal(t, ErrEmptySentence, err)
+
+	require.Len(t, audit.entries, 3)
+	assert.Equal(t, moderation.ActionReplaceWord, audit.entries[0].Action)
+	assert.Equal(t, "the darn dragon slept", audit.entries[0].Before)
+	assert.Equal(t, "the old dragon slept", audit.entries[0].After)
+	assert.Equal(t, int32(1), audit.entries[2].StoryID)
+	assert.Equal(t, "mod", audit.entries[2].Actor)
+}
+
+func TestDeleteWordReopensLastSentence(t *testing.T) {
+	// Sentence 3 finished the one Sentence Paragraph 2, the Story's last Paragraph is still to come.
+	storage := writingStoryStorage()
+	storage.SaveParagraph(Paragraph{ID: 2, Story: 1, IsFinished: true})
+	storage.SaveSentence(Sentence{ID: 3, Paragraph: 2, Content: "a time there", IsFinished: true})
+	room := Room{Name: DefaultRoom, Rules: StoryRules{TitleWords: 2, SentenceWords: 3, ParagraphSentences: 1, StoryParagraphs: 2}}
+	require.NoError(t, storage.SaveRoom(context.Background(), room))
+	srv := NewWordService(storage, log.New())
+
+	sentence, err := srv.DeleteWord(context.Background(), 3, 2, "mod")
+	require.NoError(t, err)
+	assert.False(t, sentence.IsFinished, "Expected Sentence To Be Reopened Without Enough Words")
+	paragraph, err := storage.GetParagraph(context.Background(), 2)
+	require.NoError(t, err)
+	assert.False(t, paragraph.IsFinished, "Expected Paragraph To Be Reopened With It")
+
+	// The next Word goes to the reopened Sentence, finishing it (and its Paragraph) again.
+	wrdRes, err := srv.AddWord(context.Background(), DefaultRoom, "lived")
+	require.NoError(t, err)
+	assert.Equal(t, "a time lived", wrdRes.Content)
+	paragraph, err = storage.GetParagraph(context.Background(), 2)
+	require.NoError(t, err)
+	assert.True(t, paragraph.IsFinished)
+
+	// Sentence 3 is not the last one anymore, it stays finished.
+	_, err = srv.AddWord(context.Background(), DefaultRoom, "Then")
+	require.NoError(t, err)
+	sentence, err = srv.DeleteWord(context.Background(), 3, 2, "mod")
+	require.NoError(t, err)
+	assert.True(t, sentence.IsFinished, "Expected A Sentence Followed By Others To Stay Finished")
+}
+
+func TestRenameStory(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.SaveStory(Story{ID: 1, Room: DefaultRoom, Title: "Darn it", TitleAdded: true, State: StateWriting})
+	audit := new(recordingAuditStorage)
+	srv := NewWordService(storage, log.New())
+	srv.EnableAudit(audit)
+
+	story, err := srv.RenameStory(context.Background(), 1, "Dragon  tales!", "mod")
+	require.NoError(t, err)
+	assert.Equal(t, "Dragon tales!", story.Title)
+
+	_, err = srv.RenameStory(context.Background(), 1, "Far too long", "mod") // DefaultStoryRules allow 2 Title Words
+	assert.Equal(t, ErrInvalidTitleLength, err)
+	_, err = srv.RenameStory(context.Background(), 1, " ", "mod")
+	assert.Equal(t, ErrInvalidTitleLength, err)
+
+	require.Len(t, audit.entries, 1)
+	assert.Equal(t, moderation.ActionRenameStory, audit.entries[0].Action)
+	assert.Equal(t, "Darn it", audit.entries[0].Before)
+}
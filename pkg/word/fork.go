@@ -0,0 +1,85 @@
+package word
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+)
+
+var (
+	ErrInvalidForkPosition = errors.New("Invalid Fork Position")
+	ErrRoomBusy            = errors.New("Room Already Has An Active Story")
+)
+
+// Where to fork a Story from (0 based, the Sentence is the last one copied), and the Room of the fork.
+type ForkRequest struct {
+	Paragraph int32  `json:"paragraph"`
+	Sentence  int32  `json:"sentence"`
+	Room      string `json:"room"` // A new Room is named if empty.
+}
+
+// Creates a new Story copying a Story's Title and content up to a Sentence, as the active Story of its own Room.
+// The Room (if not configured yet) gets the StoryRules of the parent's Room.
+func (srv *WordService) ForkStory(ctx context.Context, storyId int32, fork ForkRequest) (*Story, error) {
+	parent, err := srv.storage.GetStory(ctx, storyId)
+	if err != nil || !parent.State.Visible() {
+		return nil, ErrStoryNotFound
+	}
+
+	if fork.Room == "" {
+		suffix := make([]byte, 4)
+		if _, err := rand.Read(suffix); err != nil {
+			return nil, err
+		}
+		fork.Room = "fork-" + hex.EncodeToString(suffix)
+	} else if err := ValidateRoomName(fork.Room); err != nil {
+		return nil, err
+	}
+
+	lock := srv.roomLock(fork.Room)
+	defer lock.Unlock()
+	// No Story can be started in the Room while the fork is created.
+	srv.lockRoom(ctx, lock)
+
+	if _, err := srv.storage.GetUnfinishedStory(ctx, fork.Room); err == nil {
+		return nil, ErrRoomBusy
+	}
+	if _, err := srv.checkSlot(fork.Room, ""); err != nil {
+		return nil, ErrRoomBusy
+	}
+
+	room, err := srv.storage.GetRoom(ctx, fork.Room)
+	newRoom := err == ErrRoomNotFound
+	if newRoom {
+		if room, err = srv.getRoom(ctx, parent.Room); err != nil {
+			return nil, err
+		}
+		room = &Room{Name: fork.Room, Rules: room.Rules}
+	} else if err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Get Room.")
+		return nil, err
+	}
+
+	forkId, err := srv.storage.ForkStory(ctx, storyId, fork.Paragraph, fork.Sentence, room.Name, room.Rules)
+	if err != nil {
+		if err != ErrInvalidForkPosition {
+			logging.FromContext(ctx, srv.logger).Error("Could Not Fork Story.")
+		}
+		return nil, err
+	}
+	if newRoom {
+		if err := srv.storage.SaveRoom(ctx, *room); err != nil {
+			// Fork is already created, its Room just has the default StoryRules.
+			logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Save Fork's Room")
+		}
+	}
+	logging.FromContext(ctx, srv.logger).Info("Story ", storyId, " Forked As Story ", forkId, " In Room ", room.Name)
+	for _, invalidator := range srv.invalidators {
+		invalidator.InvalidateStory(forkId) // for Story listings
+	}
+	return srv.storage.GetStory(ctx, forkId)
+}
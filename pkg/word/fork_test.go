@@ -0,0 +1,51 @@
+package word
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/shubhamdwivedii/collab-story/pkg/paragraph"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForkStory(t *testing.T) {
+	rules := DefaultStoryRules
+	rules.SentenceWords = 20
+	// One finished Story (in a configured Room) to fork, and a busy Room.
+	storage := NewMemoryStorage()
+	storage.SaveStory(Story{ID: 1, Room: "tales", Title: "Once upon", TitleAdded: true, IsFinished: true, State: StateFinished})
+	storage.SaveParagraph(Paragraph{ID: 1, Story: 1, IsFinished: true})
+	storage.SaveSentence(Sentence{ID: 1, Paragraph: 1, IsFinished: true, Content: "a time."})
+	storage.SaveStory(Story{ID: 2, Room: "busy", Title: "Busy", TitleAdded: true, State: StateWriting})
+	require.NoError(t, storage.SaveRoom(context.Background(), Room{Name: "tales", Rules: rules}))
+	srv := NewWordService(storage, log.New())
+
+	fork, err := srv.ForkStory(context.Background(), 1, ForkRequest{Room: "tales-again"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fork.Parent)
+	assert.Equal(t, "tales-again", fork.Room)
+	forkRoom, err := storage.GetRoom(context.Background(), "tales-again")
+	require.NoError(t, err)
+	assert.Equal(t, rules, forkRoom.Rules, "Expected Fork's Room To Get Parent's Rules")
+	sentences, err := storage.GetStorySentences(context.Background(), fork.ID)
+	require.NoError(t, err)
+	require.Len(t, sentences, 1)
+	assert.Equal(t, "a time.", sentences[0].Content)
+
+	fork, err = srv.ForkStory(context.Background(), 1, ForkRequest{})
+	require.NoError(t, err)
+	assert.NoError(t, ValidateRoomName(fork.Room), "Expected A Valid Room To Be Named")
+
+	_, err = srv.ForkStory(context.Background(), 1, ForkRequest{Room: "busy"})
+	assert.Equal(t, ErrRoomBusy, err)
+	_, err = srv.ForkStory(context.Background(), 1, ForkRequest{Room: "Not Valid"})
+	assert.Equal(t, ErrInvalidRoomName, err)
+	_, err = srv.ForkStory(context.Background(), 1, ForkRequest{Paragraph: 5, Room: "elsewhere"})
+	assert.Equal(t, ErrInvalidForkPosition, err)
+	_, err = srv.ForkStory(context.Background(), 42, ForkRequest{})
+	assert.Equal(t, ErrStoryNotFound, err)
+}
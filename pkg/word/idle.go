@@ -0,0 +1,153 @@
+package word
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+)
+
+// Moves a Story to another State, if allowed from its current State (e.g. an admin hides an offensive Story).
+// Closing a Story (as finished or archived) finishes its partially written Paragraph and Sentence.
+func (srv *WordService) CloseStory(ctx context.Context, storyId int32, state State) (*Story, error) {
+	if !state.Valid() {
+		return nil, ErrInvalidState
+	}
+
+	story, err := srv.storage.GetStory(ctx, storyId)
+	if err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Get Story")
+		return nil, ErrStoryNotFound
+	}
+
+	lock := srv.roomLock(story.Room)
+	defer lock.Unlock()
+	// A Word being added to this Story (or a vote closing) must not interleave with changing its State.
+	srv.lockRoom(ctx, lock)
+
+	return srv.closeStory(ctx, storyId, state)
+}
+
+// Moves a Story to another State, caller must hold the lock of Story's Room.
+func (srv *WordService) closeStory(ctx context.Context, storyId int32, state State) (*Story, error) {
+	story, err := srv.storage.GetStory(ctx, storyId)
+	if err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Get Story")
+		return nil, ErrStoryNotFound
+	}
+
+	if !CanTransition(story.State, state) {
+		logging.FromContext(ctx, srv.logger).Info("Story ", storyId, " Cannot Transition From ", story.State, " To ", state)
+		return nil, ErrInvalidTransition
+	}
+
+	if err := srv.storage.UpdateStoryState(ctx, storyId, state); err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Update Story State.")
+		return nil, err
+	}
+	for _, invalidator := range srv.invalidators {
+		invalidator.InvalidateStory(storyId)
+	}
+	srv.countClosedStory(state)
+	return srv.storage.GetStory(ctx, storyId)
+}
+
+// Closes Unfinished Stories (of all Rooms) nobody has added a Word to for idle.
+// A Story still collecting its Title has no content, so it is always archived (abandoned).
+// Returns the closed Stories. Stories that fail to close don't stop the others from closing,
+// the failures are returned as a *SweepError.
+func (srv *WordService) CloseIdleStories(ctx context.Context, idle time.Duration, state State) ([]Story, error) {
+	stories, err := srv.storage.GetUnfinishedStories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var closed []Story
+	var errs []error
+	for _, story := range stories {
+		if time.Since(story.UpdatedAt) < idle {
+			continue
+		}
+		if s, err := srv.closeIdleStory(ctx, story, idle, state); err != nil {
+			logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Close Idle Story ", story.ID)
+			errs = append(errs, fmt.Errorf("Story %d: %w", story.ID, err))
+		} else if s != nil {
+			closed = append(closed, *s)
+		}
+	}
+	return closed, sweepError(errs)
+}
+
+func (srv *WordService) closeIdleStory(ctx context.Context, story Story, idle time.Duration, state State) (*Story, error) {
+	lock := srv.roomLock(story.Room)
+	defer lock.Unlock()
+	srv.lockRoom(ctx, lock)
+
+	// A Word may have been added before the lock was acquired.
+	current, err := srv.storage.GetStory(ctx, story.ID)
+	if err != nil || !current.State.InProgress() || time.Since(current.UpdatedAt) < idle {
+		return nil, nil
+	}
+
+	if current.State == StateCollectingTitle {
+		state = StateArchived
+	}
+	logging.FromContext(ctx, srv.logger).Info("Story ", current.ID, " Idle Since ", current.UpdatedAt, ", Closing As ", state)
+	return srv.closeStory(ctx, current.ID, state)
+}
+
+// Periodically closes Stories that nobody has added a Word to for a while.
+type IdleFinisher struct {
+	service *WordService
+	idle    time.Duration
+	state   State // StateFinished or StateArchived (abandoned)
+	logger  *log.Logger
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func NewIdleFinisher(service *WordService, idle time.Duration, state State, logger *log.Logger) *IdleFinisher {
+	finisher := new(IdleFinisher)
+	finisher.service = service
+	finisher.idle = idle
+	finisher.state = state
+	finisher.logger = logger
+	finisher.stop = make(chan struct{})
+	finisher.done = make(chan struct{})
+	return finisher
+}
+
+func (f *IdleFinisher) Start() {
+	// Check often enough that a Story is closed soon after it goes idle.
+	interval := f.idle / 10
+	if interval < time.Second {
+		interval = time.Second
+	} else if interval > time.Minute {
+		interval = time.Minute
+	}
+
+	go func() {
+		defer close(f.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := f.service.CloseIdleStories(context.Background(), f.idle, f.state); err != nil {
+					f.logger.WithError(err).Error("Could Not Close Idle Stories")
+				}
+			case <-f.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stops checking for idle Stories, waiting for a check in progress to complete.
+func (f *IdleFinisher) Stop() {
+	close(f.stop)
+	<-f.done
+}
@@ -0,0 +1,115 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseIdleStory(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.SaveStory(Story{ID: 1, Room: DefaultRoom, State: StateWriting, UpdatedAt: time.Now()})
+	srv := NewWordService(storage, log.New())
+
+	stories, err := srv.CloseIdleStories(context.Background(), time.Hour, StateFinished)
+	require.NoError(t, err)
+	assert.Empty(t, stories, "Expected Active Story Not To Be Closed")
+
+	storage.SaveStory(Story{ID: 1, Room: DefaultRoom, State: StateWriting, UpdatedAt: time.Now().Add(-2 * time.Hour)})
+	stories, err = srv.CloseIdleStories(context.Background(), time.Hour, StateFinished)
+	require.NoError(t, err)
+	require.Len(t, stories, 1)
+	assert.Equal(t, StateFinished, stories[0].State, "Expected Idle Story To Be Finished")
+
+	stories, err = srv.CloseIdleStories(context.Background(), time.Hour, StateFinished)
+	require.NoError(t, err)
+	assert.Empty(t, stories, "Expected No Story Left To Close")
+}
+
+// Fails to change the State of Story failing.
+type failingStateStorage struct {
+	*MemoryStorage
+	failing int32
+}
+
+func (s *failingStateStorage) UpdateStoryState(ctx context.Context, storyId int32, state State) error {
+	if storyId == s.failing {
+		return errors.New("Error Updating Story State In DB")
+	}
+	return s.MemoryStorage.UpdateStoryState(ctx, storyId, state)
+}
+
+func TestCloseIdleStoriesPastFailures(t *testing.T) {
+	storage := &failingStateStorage{MemoryStorage: NewMemoryStorage(), failing: 1}
+	storage.SaveStory(Story{ID: 1, Room: DefaultRoom, State: StateWriting, UpdatedAt: time.Now().Add(-2 * time.Hour)})
+	storage.SaveStory(Story{ID: 2, Room: "other", State: StateWriting, UpdatedAt: time.Now().Add(-2 * time.Hour)})
+	srv := NewWordService(storage, log.New())
+
+	stories, err := srv.CloseIdleStories(context.Background(), time.Hour, StateFinished)
+	var sweepErr *SweepError
+	require.ErrorAs(t, err, &sweepErr)
+	assert.Len(t, sweepErr.Errors, 1)
+	require.Len(t, stories, 1, "Expected Other Idle Story To Be Closed")
+	assert.Equal(t, int32(2), stories[0].ID)
+}
+
+func TestCloseIdleStoryWithoutContent(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.SaveStory(Story{ID: 1, Room: DefaultRoom, State: StateCollectingTitle})
+	srv := NewWordService(storage, log.New())
+
+	_, err := srv.CloseStory(context.Background(), 1, StateFinished)
+	require.Equal(t, ErrInvalidTransition, err, "Expected Story Without Content Not To Be Finished")
+
+	stories, err := srv.CloseIdleStories(context.Background(), time.Hour, StateFinished)
+	require.NoError(t, err)
+	require.Len(t, stories, 1)
+	assert.Equal(t, StateArchived, stories[0].State, "Expected Story Without Content To Be Abandoned")
+}
+
+// Records the Stories it was told changed.
+type recordingInvalidator struct {
+	stories []int32
+}
+
+func (i *recordingInvalidator) InvalidateStory(storyId int32) {
+	i.stories = append(i.stories, storyId)
+}
+
+func TestStoryStateTransitions(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.SaveStory(Story{ID: 1, Room: DefaultRoom, Title: "Once", State: StateCollectingTitle})
+	invalidator := new(recordingInvalidator)
+	srv := NewWordService(storage, log.New())
+	srv.AddInvalidator(invalidator)
+
+	_, err := srv.CloseStory(context.Background(), 1, State("deleted"))
+	require.Equal(t, ErrInvalidState, err)
+
+	story, err := srv.CloseStory(context.Background(), 1, StateWriting)
+	require.NoError(t, err)
+	assert.True(t, story.TitleAdded, "Expected Title To Be Finished Along With It")
+	wrdRes, err := srv.AddWord(context.Background(), DefaultRoom, "upon")
+	require.NoError(t, err)
+	assert.Equal(t, "Once", wrdRes.Title)
+	assert.Equal(t, "upon", wrdRes.Content)
+
+	_, err = srv.CloseStory(context.Background(), 1, StateCollectingTitle)
+	require.Equal(t, ErrInvalidTransition, err, "Expected Story Not To Go Back To Collecting Title")
+
+	story, err = srv.CloseStory(context.Background(), 1, StateHidden)
+	require.NoError(t, err)
+	assert.Equal(t, StateHidden, story.State)
+	assert.False(t, story.State.Visible())
+
+	story, err = srv.CloseStory(context.Background(), 1, StateArchived)
+	require.NoError(t, err)
+	assert.Equal(t, StateArchived, story.State)
+	assert.Equal(t, []int32{1, 1, 1, 1}, invalidator.stories, "Expected Every Change To Invalidate The Story")
+}
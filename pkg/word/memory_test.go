@@ -0,0 +1,601 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/shubhamdwivedii/collab-story/pkg/paragraph"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// In-Memory WordStorage for tests (content is not persisted, nor shared between instances).
+// Stories, Paragraphs and Sentences are finished as per rules, the same way MySQLStorage does.
+type MemoryStorage struct {
+	stories    map[int32]Story
+	paragraphs map[int32]Paragraph
+	sentences  map[int32]Sentence
+	rooms      map[string]Room
+	lastIds    [3]int32 // of stories, paragraphs and sentences
+	sync.Mutex
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	s := new(MemoryStorage)
+	s.stories = make(map[int32]Story)
+	s.paragraphs = make(map[int32]Paragraph)
+	s.sentences = make(map[int32]Sentence)
+	s.rooms = make(map[string]Room)
+	return s
+}
+
+const (
+	storyIds = iota
+	paragraphIds
+	sentenceIds
+)
+
+func (s *MemoryStorage) nextId(table int) int32 {
+	s.lastIds[table]++
+	return s.lastIds[table]
+}
+
+func (s *MemoryStorage) seenId(table int, id int32) {
+	if id > s.lastIds[table] {
+		s.lastIds[table] = id
+	}
+}
+
+// Saves a Story as is (replacing the one with its ID), e.g. to set up a test.
+func (s *MemoryStorage) SaveStory(story Story) {
+	defer s.Unlock()
+	s.Lock()
+	s.stories[story.ID] = story
+	s.seenId(storyIds, story.ID)
+}
+
+// Saves a Paragraph as is (replacing the one with its ID).
+func (s *MemoryStorage) SaveParagraph(paragraph Paragraph) {
+	defer s.Unlock()
+	s.Lock()
+	s.paragraphs[paragraph.ID] = paragraph
+	s.seenId(paragraphIds, paragraph.ID)
+}
+
+// Saves a Sentence as is (replacing the one with its ID).
+func (s *MemoryStorage) SaveSentence(sentence Sentence) {
+	defer s.Unlock()
+	s.Lock()
+	s.sentences[sentence.ID] = sentence
+	s.seenId(sentenceIds, sentence.ID)
+}
+
+// Bumps a Story's Version and UpdatedAt, as every change to its content does.
+func (s *MemoryStorage) touch(storyId int32) {
+	story := s.stories[storyId]
+	story.Version++
+	story.UpdatedAt = time.Now()
+	s.stories[storyId] = story
+}
+
+func (s *MemoryStorage) GetUnfinishedStory(ctx context.Context, room string) (*Story, error) {
+	defer s.Unlock()
+	s.Lock()
+	for _, story := range s.sortedStories() {
+		if story.Room == room && !story.IsFinished && story.State.InProgress() {
+			return &story, nil
+		}
+	}
+	return nil, ErrStoryNotFound
+}
+
+func (s *MemoryStorage) GetUnfinishedStories(ctx context.Context) ([]Story, error) {
+	defer s.Unlock()
+	s.Lock()
+	var stories []Story
+	for _, story := range s.sortedStories() {
+		if !story.IsFinished && story.State.InProgress() {
+			stories = append(stories, story)
+		}
+	}
+	return stories, nil
+}
+
+func (s *MemoryStorage) AddStory(ctx context.Context, room string, prompt Prompt) (int32, error) {
+	defer s.Unlock()
+	s.Lock()
+	story := Story{
+		ID:        s.nextId(storyIds),
+		Room:      room,
+		State:     StateCollectingTitle,
+		Prompt:    prompt.Text,
+		Keywords:  prompt.Keywords,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	s.stories[story.ID] = story
+	return story.ID, nil
+}
+
+func (s *MemoryStorage) GetStory(ctx context.Context, storyId int32) (*Story, error) {
+	defer s.Unlock()
+	s.Lock()
+	story, ok := s.stories[storyId]
+	if !ok {
+		return nil, ErrStoryNotFound
+	}
+	return &story, nil
+}
+
+func (s *MemoryStorage) UpdateStoryTitle(ctx context.Context, storyId int32, word string, rules StoryRules) error {
+	defer s.Unlock()
+	s.Lock()
+	story, ok := s.stories[storyId]
+	if !ok {
+		return ErrStoryNotFound
+	}
+	if story.TitleAdded {
+		return errors.New("Error: Story Title Is Finished Already")
+	} else if len(story.Title) == 0 && IsPunctuation(word) {
+		return ErrPunctuationOnly
+	}
+
+	story.Title = AppendWord(story.Title, word)
+	if CountWords(story.Title) >= rules.TitleWords {
+		story.TitleAdded = true
+		story.State = StateWriting
+	}
+	s.stories[storyId] = story
+	s.touch(storyId)
+	return nil
+}
+
+func (s *MemoryStorage) GetUnfinishedParagraph(ctx context.Context, storyId int32) (*Paragraph, error) {
+	defer s.Unlock()
+	s.Lock()
+	for _, paragraph := range s.storyParagraphs(storyId) {
+		if !paragraph.IsFinished {
+			return &paragraph, nil
+		}
+	}
+	return nil, ErrParagraphNotFound
+}
+
+func (s *MemoryStorage) AddParagraph(ctx context.Context, storyId int32) (int32, error) {
+	defer s.Unlock()
+	s.Lock()
+	if _, ok := s.stories[storyId]; !ok {
+		return 0, ErrStoryNotFound
+	}
+	paragraph := Paragraph{ID: s.nextId(paragraphIds), Story: storyId}
+	s.paragraphs[paragraph.ID] = paragraph
+	s.touch(storyId)
+	return paragraph.ID, nil
+}
+
+func (s *MemoryStorage) GetParagraph(ctx context.Context, paragraphId int32) (*Paragraph, error) {
+	defer s.Unlock()
+	s.Lock()
+	paragraph, ok := s.paragraphs[paragraphId]
+	if !ok {
+		return nil, ErrParagraphNotFound
+	}
+	return &paragraph, nil
+}
+
+func (s *MemoryStorage) GetUnfinishedSentence(ctx context.Context, paragraphId int32) (*Sentence, error) {
+	defer s.Unlock()
+	s.Lock()
+	for _, sentence := range s.paragraphSentences(paragraphId) {
+		if !sentence.IsFinished {
+			return &sentence, nil
+		}
+	}
+	return nil, ErrSentenceNotFound
+}
+
+func (s *MemoryStorage) AddSentence(ctx context.Context, paragraphId int32, word string, rules StoryRules) (*SentenceUpdate, error) {
+	defer s.Unlock()
+	s.Lock()
+	if IsPunctuation(word) {
+		return nil, ErrPunctuationOnly
+	}
+	paragraph, ok := s.paragraphs[paragraphId]
+	if !ok {
+		return nil, ErrParagraphNotFound
+	}
+	sentence := Sentence{ID: s.nextId(sentenceIds), Paragraph: paragraphId, Content: word}
+	sentence.IsFinished = rules.SentenceFinished(sentence.Content)
+	s.sentences[sentence.ID] = sentence
+	s.touch(paragraph.Story)
+	return s.sentenceUpdate(sentence, rules), nil
+}
+
+func (s *MemoryStorage) GetSentence(ctx context.Context, sentenceId int32) (*Sentence, error) {
+	defer s.Unlock()
+	s.Lock()
+	sentence, ok := s.sentences[sentenceId]
+	if !ok {
+		return nil, ErrSentenceNotFound
+	}
+	return &sentence, nil
+}
+
+func (s *MemoryStorage) UpdateSentence(ctx context.Context, sentenceId int32, word string, rules StoryRules) (*SentenceUpdate, error) {
+	defer s.Unlock()
+	s.Lock()
+	sentence, ok := s.sentences[sentenceId]
+	if !ok {
+		return nil, ErrSentenceNotFound
+	}
+	if CountWords(sentence.Content) >= rules.SentenceWords || sentence.IsFinished {
+		return nil, errors.New("Error: Sentence Is Already Finished")
+	}
+
+	sentence.Content = AppendWord(sentence.Content, word)
+	sentence.IsFinished = rules.SentenceFinished(sentence.Content)
+	s.sentences[sentenceId] = sentence
+	s.touch(s.paragraphs[sentence.Paragraph].Story)
+	return s.sentenceUpdate(sentence, rules), nil
+}
+
+// Finishes Paragraph (and Story) as per rules, after one of Paragraph's Sentences was just finished.
+// Returns whether the Paragraph and the Story were finished.
+func (s *MemoryStorage) finishSentence(paragraphId int32, rules StoryRules) (bool, bool) {
+	var finished int32
+	for _, sentence := range s.paragraphSentences(paragraphId) {
+		if sentence.IsFinished {
+			finished++
+		}
+	}
+	paragraph := s.paragraphs[paragraphId]
+	if finished < rules.ParagraphSentences || paragraph.IsFinished {
+		return false, false
+	}
+	paragraph.IsFinished = true
+	s.paragraphs[paragraphId] = paragraph
+
+	finished = 0
+	for _, paragraph := range s.storyParagraphs(paragraph.Story) {
+		if paragraph.IsFinished {
+			finished++
+		}
+	}
+	story := s.stories[paragraph.Story]
+	if finished < rules.StoryParagraphs || story.IsFinished {
+		return true, false
+	}
+	story.IsFinished = true
+	story.State = StateFinished
+	s.stories[story.ID] = story
+	s.touch(story.ID)
+	return true, true
+}
+
+// What adding a Word to sentence did (finishing it, its Paragraph and Story as per rules).
+func (s *MemoryStorage) sentenceUpdate(sentence Sentence, rules StoryRules) *SentenceUpdate {
+	update := &SentenceUpdate{Sentence: sentence}
+	if sentence.IsFinished {
+		update.ParagraphFinished, update.StoryFinished = s.finishSentence(sentence.Paragraph, rules)
+	}
+	update.Version = s.stories[s.paragraphs[sentence.Paragraph].Story].Version
+	return update
+}
+
+func (s *MemoryStorage) UpdateStoryState(ctx context.Context, storyId int32, state State) error {
+	defer s.Unlock()
+	s.Lock()
+	story, ok := s.stories[storyId]
+	if !ok {
+		return ErrStoryNotFound
+	}
+	if state == StateFinished || state == StateArchived {
+		for _, paragraph := range s.storyParagraphs(storyId) {
+			for _, sentence := range s.paragraphSentences(paragraph.ID) {
+				sentence.IsFinished = true
+				s.sentences[sentence.ID] = sentence
+			}
+			paragraph.IsFinished = true
+			s.paragraphs[paragraph.ID] = paragraph
+		}
+		story.IsFinished = true
+	}
+	if state == StateWriting {
+		story.TitleAdded = true
+	}
+	story.State = state
+	s.stories[storyId] = story
+	s.touch(storyId)
+	return nil
+}
+
+func (s *MemoryStorage) UndoWord(ctx context.Context, undo WordUndo) error {
+	defer s.Unlock()
+	s.Lock()
+	story, ok := s.stories[undo.StoryID]
+	if !ok {
+		return ErrStoryNotFound
+	}
+	if story.Version != undo.Version {
+		return ErrUndoConflict
+	}
+
+	if undo.Story == nil {
+		// Word created the Story (it only has a Title).
+		delete(s.stories, undo.StoryID)
+		return nil
+	}
+	delete(s.sentences, undo.NewSentence)
+	delete(s.paragraphs, undo.NewParagraph)
+	if undo.Sentence != nil {
+		s.sentences[undo.Sentence.ID] = *undo.Sentence
+	}
+	if undo.Paragraph != 0 {
+		paragraph := s.paragraphs[undo.Paragraph]
+		paragraph.IsFinished = false
+		s.paragraphs[undo.Paragraph] = paragraph
+	}
+	restored := *undo.Story
+	restored.Version = story.Version
+	s.stories[undo.StoryID] = restored
+	s.touch(undo.StoryID)
+	return nil
+}
+
+func (s *MemoryStorage) GetStorySentences(ctx context.Context, storyId int32) ([]Sentence, error) {
+	defer s.Unlock()
+	s.Lock()
+	sentences := []Sentence{}
+	for _, paragraph := range s.storyParagraphs(storyId) {
+		sentences = append(sentences, s.paragraphSentences(paragraph.ID)...)
+	}
+	sort.Slice(sentences, func(i, j int) bool { return sentences[i].ID < sentences[j].ID })
+	return sentences, nil
+}
+
+func (s *MemoryStorage) EditSentence(ctx context.Context, sentenceId int32, content string, finish bool, rules StoryRules) error {
+	defer s.Unlock()
+	s.Lock()
+	sentence, ok := s.sentences[sentenceId]
+	if !ok {
+		return ErrSentenceNotFound
+	}
+
+	wasFinished := sentence.IsFinished
+	sentence.Content = content
+	sentence.IsFinished = finish || rules.SentenceFinished(content)
+	if wasFinished && !sentence.IsFinished {
+		sentence.IsFinished = !s.reopenable(sentence)
+	}
+	s.sentences[sentenceId] = sentence
+	s.touch(s.paragraphs[sentence.Paragraph].Story)
+	if sentence.IsFinished && !wasFinished {
+		s.finishSentence(sentence.Paragraph, rules)
+	} else if !sentence.IsFinished && wasFinished {
+		s.reopenParagraph(sentence.Paragraph, rules)
+	}
+	return nil
+}
+
+// Only the last Sentence of a Story in progress can be reopened. Caller must hold the lock.
+func (s *MemoryStorage) reopenable(sentence Sentence) bool {
+	story := s.stories[s.paragraphs[sentence.Paragraph].Story]
+	if story.IsFinished || !story.State.InProgress() {
+		return false
+	}
+	for _, paragraph := range s.storyParagraphs(story.ID) {
+		for _, other := range s.paragraphSentences(paragraph.ID) {
+			if other.ID > sentence.ID {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Reopens a Paragraph without rules.ParagraphSentences finished Sentences. Caller must hold the lock.
+func (s *MemoryStorage) reopenParagraph(paragraphId int32, rules StoryRules) {
+	var finished int32
+	for _, sentence := range s.paragraphSentences(paragraphId) {
+		if sentence.IsFinished {
+			finished++
+		}
+	}
+	if paragraph := s.paragraphs[paragraphId]; paragraph.IsFinished && finished < rules.ParagraphSentences {
+		paragraph.IsFinished = false
+		s.paragraphs[paragraphId] = paragraph
+	}
+}
+
+func (s *MemoryStorage) RenameStory(ctx context.Context, storyId int32, title string, rules StoryRules) error {
+	defer s.Unlock()
+	s.Lock()
+	story, ok := s.stories[storyId]
+	if !ok {
+		return ErrStoryNotFound
+	}
+
+	story.Title = title
+	if !story.TitleAdded && CountWords(title) >= rules.TitleWords {
+		story.TitleAdded = true
+		if story.State == StateCollectingTitle {
+			story.State = StateWriting
+		}
+	}
+	s.stories[storyId] = story
+	s.touch(storyId)
+	return nil
+}
+
+func (s *MemoryStorage) GetRoom(ctx context.Context, name string) (*Room, error) {
+	defer s.Unlock()
+	s.Lock()
+	room, ok := s.rooms[name]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+	return &room, nil
+}
+
+// Creates a Room, or updates its StoryRules (keeping its prompt).
+func (s *MemoryStorage) SaveRoom(ctx context.Context, room Room) error {
+	defer s.Unlock()
+	s.Lock()
+	if saved, ok := s.rooms[room.Name]; ok {
+		room.Prompt = saved.Prompt
+	}
+	s.rooms[room.Name] = room
+	return nil
+}
+
+func (s *MemoryStorage) SaveRoomPrompt(ctx context.Context, room string, rules StoryRules, prompt *Prompt) error {
+	defer s.Unlock()
+	s.Lock()
+	saved, ok := s.rooms[room]
+	if !ok {
+		saved = Room{Name: room, Rules: rules}
+	}
+	saved.Prompt = prompt
+	s.rooms[room] = saved
+	return nil
+}
+
+func (s *MemoryStorage) ForkStory(ctx context.Context, parentId int32, paragraphIdx int32, sentenceIdx int32, room string, rules StoryRules) (int32, error) {
+	defer s.Unlock()
+	s.Lock()
+	parent, ok := s.stories[parentId]
+	if !ok {
+		return 0, ErrStoryNotFound
+	}
+	paragraphs := s.storyParagraphs(parentId)
+	if paragraphIdx < 0 || int(paragraphIdx) >= len(paragraphs) {
+		return 0, ErrInvalidForkPosition
+	}
+	last := s.paragraphSentences(paragraphs[paragraphIdx].ID)
+	if sentenceIdx < 0 || int(sentenceIdx) >= len(last) {
+		return 0, ErrInvalidForkPosition
+	}
+
+	// Last Paragraph can take more Sentences unless it is full, Story is finished if all its Paragraphs are.
+	lastFinished := sentenceIdx+1 >= rules.ParagraphSentences
+	fork := Story{ID: s.nextId(storyIds), Room: room, Title: parent.Title, TitleAdded: true, State: StateWriting,
+		Parent: parentId, Prompt: parent.Prompt, Keywords: parent.Keywords, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if lastFinished && paragraphIdx+1 >= rules.StoryParagraphs {
+		fork.IsFinished = true
+		fork.State = StateFinished
+	}
+	s.stories[fork.ID] = fork
+
+	for i, paragraph := range paragraphs[:paragraphIdx+1] {
+		sentences := s.paragraphSentences(paragraph.ID)
+		if int32(i) == paragraphIdx {
+			sentences = sentences[:sentenceIdx+1]
+		}
+		copied := Paragraph{ID: s.nextId(paragraphIds), Story: fork.ID, IsFinished: int32(i) < paragraphIdx || lastFinished}
+		s.paragraphs[copied.ID] = copied
+		for _, sentence := range sentences {
+			// Copied Sentences are all finished, the next Word starts a new one.
+			id := s.nextId(sentenceIds)
+			s.sentences[id] = Sentence{ID: id, Paragraph: copied.ID, IsFinished: true, Content: sentence.Content}
+		}
+	}
+	return fork.ID, nil
+}
+
+// All Stories, in order of creation. Caller must hold the lock.
+func (s *MemoryStorage) sortedStories() []Story {
+	stories := make([]Story, 0, len(s.stories))
+	for _, story := range s.stories {
+		stories = append(stories, story)
+	}
+	sort.Slice(stories, func(i, j int) bool { return stories[i].ID < stories[j].ID })
+	return stories
+}
+
+// A Story's Paragraphs, in order. Caller must hold the lock.
+func (s *MemoryStorage) storyParagraphs(storyId int32) []Paragraph {
+	var paragraphs []Paragraph
+	for _, paragraph := range s.paragraphs {
+		if paragraph.Story == storyId {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	sort.Slice(paragraphs, func(i, j int) bool { return paragraphs[i].ID < paragraphs[j].ID })
+	return paragraphs
+}
+
+// A Paragraph's Sentences, in order. Caller must hold the lock.
+func (s *MemoryStorage) paragraphSentences(paragraphId int32) []Sentence {
+	var sentences []Sentence
+	for _, sentence := range s.sentences {
+		if sentence.Paragraph == paragraphId {
+			sentences = append(sentences, sentence)
+		}
+	}
+	sort.Slice(sentences, func(i, j int) bool { return sentences[i].ID < sentences[j].ID })
+	return sentences
+}
+
+// Storage with a single Story being written (Paragraph 2, Sentence 3 "a time"), at Version 5.
+func writingStoryStorage() *MemoryStorage {
+	storage := NewMemoryStorage()
+	storage.SaveStory(Story{ID: 1, Room: DefaultRoom, Title: "Once upon", TitleAdded: true, State: StateWriting, Version: 5})
+	storage.SaveParagraph(Paragraph{ID: 2, Story: 1})
+	storage.SaveSentence(Sentence{ID: 3, Paragraph: 2, Content: "a time"})
+	return storage
+}
+
+// Changes a Story the way a moderator's edit would (bumping its Version).
+func editStory(t *testing.T, storage *MemoryStorage, storyId int32) {
+	story, err := storage.GetStory(context.Background(), storyId)
+	require.NoError(t, err)
+	story.Version++
+	storage.SaveStory(*story)
+}
+
+// Content of a Sentence in storage.
+func sentenceContent(t *testing.T, storage *MemoryStorage, sentenceId int32) string {
+	sentence, err := storage.GetSentence(context.Background(), sentenceId)
+	require.NoError(t, err)
+	return sentence.Content
+}
+
+func TestMemoryStorageFinishesStory(t *testing.T) {
+	rules := StoryRules{TitleWords: 1, SentenceWords: 2, ParagraphSentences: 1, StoryParagraphs: 2}
+	storage := NewMemoryStorage()
+	ctx := context.Background()
+
+	storyId, err := storage.AddStory(ctx, DefaultRoom, Prompt{Text: "space pirates"})
+	require.NoError(t, err)
+	require.NoError(t, storage.UpdateStoryTitle(ctx, storyId, "Ahoy", rules))
+	for i := 0; i < 2; i++ {
+		paragraphId, err := storage.AddParagraph(ctx, storyId)
+		require.NoError(t, err)
+		added, err := storage.AddSentence(ctx, paragraphId, "yo", rules)
+		require.NoError(t, err)
+		assert.False(t, added.Sentence.IsFinished)
+		update, err := storage.UpdateSentence(ctx, added.Sentence.ID, "ho!", rules)
+		require.NoError(t, err)
+		assert.True(t, update.Sentence.IsFinished)
+		assert.True(t, update.ParagraphFinished)
+		assert.Equal(t, i == 1, update.StoryFinished)
+	}
+
+	story, err := storage.GetStory(ctx, storyId)
+	require.NoError(t, err)
+	assert.True(t, story.IsFinished)
+	assert.Equal(t, StateFinished, story.State)
+	_, err = storage.GetUnfinishedStory(ctx, DefaultRoom)
+	assert.Equal(t, ErrStoryNotFound, err)
+
+	sentences, err := storage.GetStorySentences(ctx, storyId)
+	require.NoError(t, err)
+	require.Len(t, sentences, 2)
+	assert.Equal(t, "yo ho!", sentences[1].Content)
+}
@@ -0,0 +1,72 @@
+package word
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+)
+
+// Lock waits are much shorter than requests.
+var lockWaitBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// Exposes Words added, Story parts finished and Room lock wait time in reg.
+func (srv *WordService) EnableMetrics(reg prometheus.Registerer) {
+	factory := promauto.With(reg)
+	srv.wordsAdded = factory.NewCounter(prometheus.CounterOpts{
+		Name: "collab_words_added_total",
+		Help: "Words added to Stories.",
+	})
+	srv.partsFinished = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "collab_parts_finished_total",
+		Help: "Stories, Paragraphs and Sentences finished.",
+	}, []string{"part"})
+	srv.lockWait = factory.NewHistogram(prometheus.HistogramOpts{
+		Name:    "collab_room_lock_wait_seconds",
+		Help:    "Time spent waiting for a Room's lock.",
+		Buckets: lockWaitBuckets,
+	})
+}
+
+// Locks a Room's lock (from roomLock), measuring (and tracing) how long it took.
+func (srv *WordService) lockRoom(ctx context.Context, lock *sync.Mutex) {
+	_, span := tracing.Start(ctx, "WordService.lockRoom")
+	defer span.End()
+	startTime := time.Now()
+	waiter := srv.startWaiting(startTime)
+	lock.Lock()
+	srv.stopWaiting(waiter)
+	if srv.lockWait != nil {
+		srv.lockWait.Observe(time.Since(startTime).Seconds())
+	}
+}
+
+// Counts an added Word, and the Sentence, Paragraph and Story it finished (if metrics are enabled).
+func (srv *WordService) countWord(undo *WordUndo) {
+	if srv.wordsAdded == nil {
+		return
+	}
+	srv.wordsAdded.Inc()
+
+	if undo.Update == nil || !undo.Update.Sentence.IsFinished {
+		return // Word went to the Title, or did not finish its Sentence.
+	}
+	srv.partsFinished.WithLabelValues("sentence").Inc()
+	if undo.Update.ParagraphFinished {
+		srv.partsFinished.WithLabelValues("paragraph").Inc()
+	}
+	if undo.Update.StoryFinished {
+		srv.partsFinished.WithLabelValues("story").Inc()
+	}
+}
+
+// Counts a Story closed by a moderator or for being idle.
+func (srv *WordService) countClosedStory(state State) {
+	if srv.partsFinished != nil && state == StateFinished {
+		srv.partsFinished.WithLabelValues("story").Inc()
+	}
+}
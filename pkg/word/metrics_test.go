@@ -0,0 +1,38 @@
+package word
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordMetrics(t *testing.T) {
+	srv := NewWordService(writingStoryStorage(), log.New())
+	srv.CloseStory(context.Background(), 1, StateFinished) // metrics are not enabled yet, so nothing is counted
+
+	storage := writingStoryStorage()
+	rules := DefaultStoryRules
+	rules.SentenceWords = 3
+	storage.SaveRoom(context.Background(), Room{Name: DefaultRoom, Rules: rules})
+	srv = NewWordService(storage, log.New())
+	reg := prometheus.NewRegistry()
+	srv.EnableMetrics(reg)
+
+	_, err := srv.AddWord(context.Background(), DefaultRoom, "there") // finishes "a time there"
+	require.NoError(t, err)
+	_, err = srv.AddWord(context.Background(), DefaultRoom, "was")
+	require.NoError(t, err)
+	_, err = srv.CloseStory(context.Background(), 1, StateFinished)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(srv.wordsAdded))
+	assert.Equal(t, float64(1), testutil.ToFloat64(srv.partsFinished.WithLabelValues("sentence")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(srv.partsFinished.WithLabelValues("story")), "Expected Closed Story To Count")
+	assert.Equal(t, 1, testutil.CollectAndCount(srv.lockWait))
+}
@@ -0,0 +1,72 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+)
+
+// Runs every Word through filter before it is added, rejected Words are recorded in rejections (if not nil).
+func (srv *WordService) EnableModeration(filter moderation.WordFilter, rejections moderation.RejectionStorage) {
+	srv.filter = filter
+	srv.rejections = rejections
+}
+
+// Returns a *moderation.RejectedError if the filter rejects word.
+func (srv *WordService) moderateWord(ctx context.Context, room string, word string) error {
+	if srv.filter == nil {
+		return nil
+	}
+	word = NormalizeWord(word) // as it would be stored
+	return srv.rejectWord(ctx, room, word, srv.filter.FilterWord(word))
+}
+
+// Same as moderateWord, for filters that check Words against the Story they are added to.
+// Caller must hold the Room's lock.
+func (srv *WordService) moderateWordInContext(ctx context.Context, word string, wordCtx moderation.WordContext) error {
+	filter, ok := srv.filter.(moderation.ContextFilter)
+	if !ok {
+		return nil
+	}
+	return srv.rejectWord(ctx, wordCtx.Room, word, filter.FilterWordInContext(word, wordCtx))
+}
+
+// Records a Word rejected with err (if not nil) for moderators.
+func (srv *WordService) rejectWord(ctx context.Context, room string, word string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var rejected *moderation.RejectedError
+	if !errors.As(err, &rejected) {
+		// Custom filters may return any error, treat it as a rejection.
+		rejected = &moderation.RejectedError{Filter: "custom", Reason: err.Error()}
+	}
+	logging.FromContext(ctx, srv.logger).Info("Word Rejected By " + rejected.Filter + " Filter.")
+
+	if srv.rejections != nil {
+		rejectedWord := moderation.RejectedWord{
+			Room:      room,
+			Word:      word,
+			Filter:    rejected.Filter,
+			Reason:    rejected.Reason,
+			CreatedAt: time.Now(),
+		}
+		if err := srv.rejections.AddRejectedWord(ctx, rejectedWord); err != nil {
+			logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Record Rejected Word")
+		}
+	}
+	return rejected
+}
+
+// Rejected Words (latest first) for moderators.
+func (srv *WordService) GetRejectedWords(ctx context.Context, limit int32, offset int32) ([]moderation.RejectedWord, error) {
+	if srv.rejections == nil {
+		return []moderation.RejectedWord{}, nil
+	}
+	return srv.rejections.GetRejectedWords(ctx, limit, offset)
+}
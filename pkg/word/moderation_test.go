@@ -0,0 +1,46 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingRejectionStorage struct {
+	rejected []moderation.RejectedWord
+}
+
+func (s *recordingRejectionStorage) AddRejectedWord(ctx context.Context, rejected moderation.RejectedWord) error {
+	s.rejected = append(s.rejected, rejected)
+	return nil
+}
+
+func (s *recordingRejectionStorage) GetRejectedWords(ctx context.Context, limit int32, offset int32) ([]moderation.RejectedWord, error) {
+	return s.rejected, nil
+}
+
+func TestModerateWord(t *testing.T) {
+	// Words are moderated before Storage is touched, so no Storage is needed.
+	srv := NewWordService(nil, log.New())
+	rejections := new(recordingRejectionStorage)
+	srv.EnableModeration(moderation.NewBlocklist([]string{"darn"}), rejections)
+
+	_, err := srv.AddWord(context.Background(), DefaultRoom, "d4rn!")
+	var rejected *moderation.RejectedError
+	require.True(t, errors.As(err, &rejected))
+
+	_, err = srv.AddWords(context.Background(), "kids", []string{"fine", "darn"})
+	require.True(t, errors.As(err, &rejected), "Expected Batch Error To Wrap Rejection")
+
+	require.Len(t, rejections.rejected, 2)
+	assert.Equal(t, DefaultRoom, rejections.rejected[0].Room)
+	assert.Equal(t, "d4rn!", rejections.rejected[0].Word)
+	assert.Equal(t, "kids", rejections.rejected[1].Room)
+	assert.Equal(t, "blocklist", rejections.rejected[1].Filter)
+}
@@ -0,0 +1,125 @@
+package word
+
+import (
+	"context"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+)
+
+const PositionMismatchCode = "position_mismatch"
+
+// Where the next Word of a Room lands.
+type Position struct {
+	StoryID    int32 `json:"story_id"` // 0 if the Word will start a new Story.
+	Version    int32 `json:"version"`  // Story's Version, changes with every Word.
+	InTitle    bool  `json:"in_title"`
+	SentenceID int32 `json:"sentence_id,omitempty"` // 0 if the Word will start a new Sentence (or goes to the Title).
+	Word       int32 `json:"word"`                  // Position of the Word in the Title or Sentence, starting at 1.
+}
+
+// True if the next Word would still land at expected, the same Story at the same Version (e.g. from a WordResponse's
+// next_position). Indexes alone are not enough, a Sentence may be back at the same Word after an undo or an edit.
+func (p Position) Matches(expected Position) bool {
+	return p.StoryID == expected.StoryID && p.Version == expected.Version
+}
+
+// Optional conditions on where a Word lands.
+type WordOptions struct {
+	SlotToken        string    // Word lands at the position reserved with this token (see ReserveSlot).
+	ExpectedPosition *Position // Word is only added if the next position still matches.
+}
+
+// Returned (with the Room's current state) when a Word's expected position no longer matches.
+type PositionMismatchError struct {
+	Current WordResponse
+}
+
+func (e *PositionMismatchError) Error() string {
+	return "Story Moved On Since Expected Position"
+}
+
+// Room's current Story and Sentence, with where the next Word will land. Caller must hold the Room's lock.
+func (srv *WordService) currentState(ctx context.Context, room *Room) (*WordResponse, error) {
+	position := &Position{InTitle: true, Word: 1}
+	wrdRes := &WordResponse{Room: room.Name, NextPosition: position}
+	story, err := srv.storage.GetUnfinishedStory(ctx, room.Name)
+	if err == ErrStoryNotFound {
+		return wrdRes, nil // Word will start a new Story.
+	} else if err != nil {
+		return nil, err
+	}
+	wrdRes.ID = story.ID
+	wrdRes.Title = story.Title
+	wrdRes.Prompt = story.Prompt
+	position.StoryID = story.ID
+	position.Version = story.Version
+	if !story.TitleAdded {
+		position.Word = CountWords(story.Title) + 1
+		return wrdRes, nil
+	}
+
+	position.InTitle = false
+	paragraph, err := srv.storage.GetUnfinishedParagraph(ctx, story.ID)
+	if err == ErrParagraphNotFound {
+		return wrdRes, nil // Word will start a new Paragraph.
+	} else if err != nil {
+		return nil, err
+	}
+	sentence, err := srv.storage.GetUnfinishedSentence(ctx, paragraph.ID)
+	if err == ErrSentenceNotFound {
+		return wrdRes, nil // Word will start a new Sentence.
+	} else if err != nil {
+		return nil, err
+	}
+	wrdRes.Content = sentence.Content
+	position.SentenceID = sentence.ID
+	position.Word = CountWords(sentence.Content) + 1
+	return wrdRes, nil
+}
+
+// Where the next Word lands after a Word was added to story's Title (story as it is after the Word).
+func positionAfterTitle(story *Story) *Position {
+	if !story.TitleAdded {
+		return &Position{StoryID: story.ID, Version: story.Version, InTitle: true, Word: CountWords(story.Title) + 1}
+	}
+	return &Position{StoryID: story.ID, Version: story.Version, Word: 1} // starts the first Sentence
+}
+
+// Where the next Word lands after a Word was added to a Sentence of a Story.
+func positionAfterSentence(storyId int32, update *SentenceUpdate) *Position {
+	if update.StoryFinished {
+		return &Position{InTitle: true, Word: 1} // starts a new Story
+	}
+	if update.Sentence.IsFinished {
+		return &Position{StoryID: storyId, Version: update.Version, Word: 1} // starts a new Sentence
+	}
+	return &Position{StoryID: storyId, Version: update.Version, SentenceID: update.Sentence.ID, Word: CountWords(update.Sentence.Content) + 1}
+}
+
+// Where the next Word of a Room will land, caller must hold the Room's lock.
+func (srv *WordService) nextPosition(ctx context.Context, room *Room) (Position, error) {
+	current, err := srv.currentState(ctx, room)
+	if err != nil {
+		return Position{}, err
+	}
+	return *current.NextPosition, nil
+}
+
+// Returns a *PositionMismatchError unless the next Word of a Room lands at expected (nil expects nothing).
+// Caller must hold the Room's lock.
+func (srv *WordService) checkPosition(ctx context.Context, room *Room, expected *Position) error {
+	if expected == nil {
+		return nil
+	}
+	current, err := srv.currentState(ctx, room)
+	if err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Get Room's Current Position")
+		return err
+	}
+	if !current.NextPosition.Matches(*expected) {
+		return &PositionMismatchError{Current: *current}
+	}
+	return nil
+}
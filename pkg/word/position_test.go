@@ -0,0 +1,88 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositionMatches(t *testing.T) {
+	current := Position{StoryID: 1, Version: 6, SentenceID: 3, Word: 4}
+
+	assert.True(t, current.Matches(Position{StoryID: 1, Version: 6}))
+	assert.False(t, current.Matches(Position{StoryID: 1, Version: 5}))
+	assert.False(t, current.Matches(Position{StoryID: 1, SentenceID: 3, Word: 4}), "Expected A Version To Be Required")
+	assert.False(t, current.Matches(Position{StoryID: 2, Version: 6}))
+
+	newStory := Position{InTitle: true, Word: 1}
+	assert.True(t, newStory.Matches(Position{InTitle: true, Word: 1}))
+}
+
+// Fails to find unfinished Sentences.
+type brokenSentenceStorage struct {
+	*MemoryStorage
+}
+
+func (s *brokenSentenceStorage) GetUnfinishedSentence(ctx context.Context, paragraphId int32) (*Sentence, error) {
+	return nil, errors.New("Error Reading Unfinished Sentence From DB")
+}
+
+func TestExpectedPositionStorageError(t *testing.T) {
+	storage := writingStoryStorage()
+	srv := NewWordService(&brokenSentenceStorage{storage}, log.New())
+
+	_, err := srv.AddWordWithOptions(context.Background(), DefaultRoom, "there", WordOptions{ExpectedPosition: &Position{StoryID: 1, Version: 5}})
+	require.Error(t, err)
+	var mismatch *PositionMismatchError
+	assert.False(t, errors.As(err, &mismatch), "Expected A Storage Error Not To Look Like A New Sentence")
+	assert.Equal(t, "a time", sentenceContent(t, storage, 3))
+}
+
+func TestAddWordExpectedPosition(t *testing.T) {
+	storage := writingStoryStorage()
+	srv := NewWordService(storage, log.New())
+
+	first, err := srv.AddWord(context.Background(), DefaultRoom, "there")
+	require.NoError(t, err)
+	require.NotNil(t, first.NextPosition)
+	assert.Equal(t, Position{StoryID: 1, Version: 6, SentenceID: 3, Word: 4}, *first.NextPosition)
+
+	// Someone else adds a Word before the client composing on first's Sentence.
+	_, err = srv.AddWord(context.Background(), DefaultRoom, "lived")
+	require.NoError(t, err)
+
+	_, err = srv.AddWordWithOptions(context.Background(), DefaultRoom, "was", WordOptions{ExpectedPosition: first.NextPosition})
+	var mismatch *PositionMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, "a time there lived", mismatch.Current.Content)
+	assert.Equal(t, int32(7), mismatch.Current.NextPosition.Version)
+	assert.Equal(t, "a time there lived", sentenceContent(t, storage, 3))
+
+	wrdRes, err := srv.AddWordWithOptions(context.Background(), DefaultRoom, "a", WordOptions{ExpectedPosition: mismatch.Current.NextPosition})
+	require.NoError(t, err)
+	assert.Equal(t, "a time there lived a", wrdRes.Content)
+}
+
+func TestNextPositionMatchesCurrentState(t *testing.T) {
+	storage := NewMemoryStorage()
+	rules := StoryRules{TitleWords: 2, SentenceWords: 2, ParagraphSentences: 2, StoryParagraphs: 2}
+	require.NoError(t, storage.SaveRoom(context.Background(), Room{Name: DefaultRoom, Rules: rules}))
+	srv := NewWordService(storage, log.New())
+	room, err := srv.getRoom(context.Background(), DefaultRoom)
+	require.NoError(t, err)
+
+	// Title, then 4 Sentences, and the first Word of the next Story.
+	for i := 0; i < 2+4*2+1; i++ {
+		wrdRes, err := srv.AddWord(context.Background(), DefaultRoom, "word")
+		require.NoError(t, err)
+		current, err := srv.currentState(context.Background(), room)
+		require.NoError(t, err)
+		assert.Equal(t, *current.NextPosition, *wrdRes.NextPosition, "Word %d", i+1)
+	}
+}
@@ -0,0 +1,98 @@
+package word
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+)
+
+// Sets the prompts new Stories are seeded with, in turn (unless an admin chose one for the Room).
+func (srv *WordService) SetPrompts(prompts []Prompt) {
+	srv.Lock()
+	defer srv.Unlock()
+	srv.prompts = prompts
+	srv.promptIndex = 0
+}
+
+// Chooses the prompt for the next Story created in a Room, an empty prompt clears the choice.
+func (srv *WordService) SetRoomPrompt(ctx context.Context, room string, prompt Prompt) (*Prompt, error) {
+	if err := ValidateRoomName(room); err != nil {
+		return nil, err
+	}
+	prompt.Text = strings.TrimSpace(prompt.Text)
+	for i, keyword := range prompt.Keywords {
+		prompt.Keywords[i] = strings.ToLower(strings.TrimSpace(keyword))
+	}
+	if err := prompt.Validate(); err != nil {
+		return nil, err
+	}
+
+	lock := srv.roomLock(room)
+	defer lock.Unlock()
+	// So the prompt is not set while a Story is being created with the previous one.
+	srv.lockRoom(ctx, lock)
+
+	rm, err := srv.getRoom(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	var chosen *Prompt
+	if prompt.Text != "" || len(prompt.Keywords) > 0 {
+		chosen = &prompt
+	}
+	if err := srv.storage.SaveRoomPrompt(ctx, room, rm.Rules, chosen); err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Save Room Prompt.")
+		return nil, err
+	}
+	return &prompt, nil
+}
+
+// Prompt the next Story of a Room will be seeded with (empty if there are no prompts).
+func (srv *WordService) nextPrompt(room *Room) Prompt {
+	if room.Prompt != nil {
+		return *room.Prompt
+	}
+	srv.Lock()
+	defer srv.Unlock()
+	if len(srv.prompts) == 0 {
+		return Prompt{}
+	}
+	return srv.prompts[srv.promptIndex%len(srv.prompts)]
+}
+
+// Moves on to the next prompt once a Story of room was created, caller must hold the Room's lock.
+func (srv *WordService) usePrompt(ctx context.Context, room *Room) {
+	if room.Prompt != nil {
+		if err := srv.storage.SaveRoomPrompt(ctx, room.Name, room.Rules, nil); err != nil {
+			// Story is already created, the Room's prompt will just seed its next Story too.
+			logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Clear Room Prompt")
+			return
+		}
+		room.Prompt = nil
+		return
+	}
+	srv.Lock()
+	defer srv.Unlock()
+	if len(srv.prompts) > 0 {
+		srv.promptIndex = (srv.promptIndex + 1) % len(srv.prompts)
+	}
+}
+
+// Checks a Word about to be added to story's Title against the Story's theme, caller must hold the Room's lock.
+func (srv *WordService) moderateTitleWord(ctx context.Context, room *Room, story *Story, word string) error {
+	if srv.filter == nil {
+		return nil
+	}
+	wordCtx := moderation.WordContext{
+		Room:       room.Name,
+		Prompt:     story.Prompt,
+		Keywords:   story.Keywords,
+		Title:      story.Title,
+		TitleWords: room.Rules.TitleWords - CountWords(story.Title),
+	}
+	return srv.moderateWordInContext(ctx, word, wordCtx)
+}
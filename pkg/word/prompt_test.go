@@ -0,0 +1,97 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptRotation(t *testing.T) {
+	// Every Story is a one Word Title and a two Word Sentence.
+	storage := NewMemoryStorage()
+	rules := StoryRules{TitleWords: 1, SentenceWords: 2, ParagraphSentences: 1, StoryParagraphs: 1}
+	require.NoError(t, storage.SaveRoom(context.Background(), Room{Name: DefaultRoom, Rules: rules}))
+	srv := NewWordService(storage, log.New())
+	srv.SetPrompts([]Prompt{{Text: "space pirates"}, {Text: "haunted house"}})
+
+	var prompts []string
+	for _, word := range []string{"One", "Two", "Three", "Four", "Five", "Six"} {
+		wrdRes, err := srv.AddWord(context.Background(), DefaultRoom, word)
+		require.NoError(t, err)
+		prompts = append(prompts, wrdRes.Prompt)
+	}
+	// The prompt is chosen when a Story is created.
+	assert.Equal(t, []string{"space pirates", "space pirates", "space pirates", "haunted house", "haunted house", "haunted house"}, prompts)
+
+	_, err := srv.SetRoomPrompt(context.Background(), DefaultRoom, Prompt{Text: "lost at sea", Keywords: []string{" Sea "}})
+	require.NoError(t, err)
+	wrdRes, err := srv.AddWord(context.Background(), DefaultRoom, "Seven")
+	require.NoError(t, err)
+	assert.Equal(t, "lost at sea", wrdRes.Prompt)
+	story, err := storage.GetStory(context.Background(), wrdRes.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sea"}, story.Keywords)
+
+	for _, word := range []string{"Eight", "Nine"} {
+		_, err = srv.AddWord(context.Background(), DefaultRoom, word)
+		require.NoError(t, err)
+	}
+	wrdRes, err = srv.AddWord(context.Background(), DefaultRoom, "Ten")
+	require.NoError(t, err)
+	assert.Equal(t, "space pirates", wrdRes.Prompt, "Expected Room's Prompt To Be Used Once")
+
+	_, err = srv.SetRoomPrompt(context.Background(), DefaultRoom, Prompt{Text: "sea", Keywords: []string{"two words"}})
+	assert.Equal(t, ErrInvalidPrompt, err)
+}
+
+func TestRoomPromptIsStored(t *testing.T) {
+	storage := NewMemoryStorage()
+	_, err := NewWordService(storage, log.New()).SetRoomPrompt(context.Background(), "sea", Prompt{Text: "lost at sea"})
+	require.NoError(t, err)
+	room, err := storage.GetRoom(context.Background(), "sea")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStoryRules, room.Rules, "Expected A Room That Was Never Configured To Get Default Rules")
+
+	// Changing the Room's rules keeps its prompt.
+	rules := DefaultStoryRules
+	rules.TitleWords = 1
+	require.NoError(t, storage.SaveRoom(context.Background(), Room{Name: "sea", Rules: rules}))
+
+	// Another instance (or a restart) seeds the Room's next Story with it, once.
+	srv := NewWordService(storage, log.New())
+	srv.SetPrompts([]Prompt{{Text: "space pirates"}})
+	wrdRes, err := srv.AddWord(context.Background(), "sea", "Adrift")
+	require.NoError(t, err)
+	assert.Equal(t, "lost at sea", wrdRes.Prompt)
+	room, err = storage.GetRoom(context.Background(), "sea")
+	require.NoError(t, err)
+	assert.Nil(t, room.Prompt, "Expected Room's Prompt To Be Used Once")
+	assert.Equal(t, rules, room.Rules)
+}
+
+func TestThemeFilterRejectsTitle(t *testing.T) {
+	storage := NewMemoryStorage()
+	srv := NewWordService(storage, log.New())
+	srv.EnableModeration(moderation.NewThemeFilter(), nil)
+	srv.SetPrompts([]Prompt{{Text: "space pirates", Keywords: []string{"pirate"}}})
+
+	_, err := srv.AddWord(context.Background(), DefaultRoom, "The")
+	require.NoError(t, err)
+
+	_, err = srv.AddWord(context.Background(), DefaultRoom, "End")
+	var rejected *moderation.RejectedError
+	require.True(t, errors.As(err, &rejected))
+	story, err := storage.GetStory(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "The", story.Title)
+
+	wrdRes, err := srv.AddWord(context.Background(), DefaultRoom, "Pirates")
+	require.NoError(t, err)
+	assert.Equal(t, "The Pirates", wrdRes.Title)
+}
@@ -0,0 +1,79 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+)
+
+// Number of Room locks. Rooms share the lock their name hashes to, so clients cannot grow memory
+// by sending new Room names (and a Room must never be locked while another one is).
+const roomLockShards = 256
+
+// Returns the lock of a Room, Words in different Rooms can (mostly) be added concurrently.
+func (srv *WordService) roomLock(room string) *sync.Mutex {
+	hash := fnv.New32a()
+	hash.Write([]byte(room))
+	return &srv.roomLocks[hash.Sum32()%roomLockShards]
+}
+
+var ErrLockStarvation = errors.New("Words Waiting Too Long For A Room's Lock")
+
+// Records that a Word started waiting for a Room's lock (at since), returns the waiter for stopWaiting.
+func (srv *WordService) startWaiting(since time.Time) int64 {
+	defer srv.Unlock()
+	srv.Lock()
+	srv.nextWaiter++
+	srv.lockWaiters[srv.nextWaiter] = since
+	return srv.nextWaiter
+}
+
+func (srv *WordService) stopWaiting(waiter int64) {
+	defer srv.Unlock()
+	srv.Lock()
+	delete(srv.lockWaiters, waiter)
+}
+
+// How long the Word waiting longest for a Room's lock has been waiting (0 if none is).
+func (srv *WordService) LongestLockWait() time.Duration {
+	defer srv.Unlock()
+	srv.Lock()
+	var longest time.Duration
+	for _, since := range srv.lockWaiters {
+		if wait := time.Since(since); wait > longest {
+			longest = wait
+		}
+	}
+	return longest
+}
+
+// Readiness check, fails while a Word has been waiting for a Room's lock for longer than maxWait.
+func (srv *WordService) CheckLockWait(ctx context.Context, maxWait time.Duration) error {
+	if wait := srv.LongestLockWait(); wait > maxWait {
+		return fmt.Errorf("%w (%s)", ErrLockStarvation, wait.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// Sets the StoryRules of Rooms that were never configured (DefaultStoryRules unless set).
+func (srv *WordService) SetDefaultRules(rules StoryRules) {
+	srv.defaultRules = rules
+}
+
+// Gets a Room (with default StoryRules if Room was never configured).
+func (srv *WordService) getRoom(ctx context.Context, name string) (*Room, error) {
+	room, err := srv.storage.GetRoom(ctx, name)
+	if err == ErrRoomNotFound {
+		return &Room{Name: name, Rules: srv.defaultRules}, nil
+	} else if err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Get Room.")
+		return nil, err
+	}
+	return room, nil
+}
@@ -0,0 +1,189 @@
+package word
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+)
+
+var (
+	ErrSlotsDisabled = errors.New("Slots Are Not Enabled")
+	ErrSlotNotFound  = errors.New("Slot Not Found Or Expired")
+	ErrSlotConflict  = errors.New("Story Changed Since Slot Was Reserved")
+	ErrVotingSlot    = errors.New("Slots Cannot Be Reserved In A Room That Votes On Words")
+)
+
+// Returned when the next position of a Room is reserved by another contributor.
+type SlotReservedError struct {
+	ExpiresAt time.Time
+}
+
+func (e *SlotReservedError) Error() string {
+	return "Next Position Is Reserved By Another Contributor"
+}
+
+// Returned when a client reserves a Room's next position again right after its own Slot there expired unused.
+type SlotCooldownError struct {
+	Until time.Time
+}
+
+func (e *SlotCooldownError) Error() string {
+	return "Slot Expired Unused, Wait Before Reserving Again"
+}
+
+// The next position of a Room, reserved for the contributor holding Token (sent as Slot-Token header with POST /add).
+// No other Word can be added to the Room until the Slot is used or expires.
+type Slot struct {
+	Token     string    `json:"token"`
+	Room      string    `json:"room"`
+	Position            // as reserved
+	ExpiresAt time.Time `json:"expires_at"`
+	clients   []string  // who reserved it
+}
+
+// Clients whose Slot expired unused, and until when they cannot reserve the Room's next position again.
+type slotCooldown struct {
+	clients []string
+	until   time.Time
+}
+
+// Lets contributors reserve the next position of a Room for ttl (0 disables slots).
+func (srv *WordService) EnableSlots(ttl time.Duration) {
+	srv.slotTTL = ttl
+}
+
+// Reserves the next position of a Room, returns a *SlotReservedError if it is already reserved.
+// clients identify who reserves (e.g. IP and contributor), if any of them let their last Slot of the Room expire unused
+// they cannot reserve it again for ttl (a *SlotCooldownError), so nobody can hold a Room forever.
+func (srv *WordService) ReserveSlot(ctx context.Context, room string, clients ...string) (*Slot, error) {
+	if srv.slotTTL <= 0 {
+		return nil, ErrSlotsDisabled
+	}
+	if err := ValidateRoomName(room); err != nil {
+		return nil, err
+	}
+
+	lock := srv.roomLock(room)
+	defer lock.Unlock()
+	srv.lockRoom(ctx, lock)
+
+	rm, err := srv.getRoom(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	if srv.voting(rm) {
+		return nil, ErrVotingSlot
+	}
+	if _, err := srv.checkSlot(room, ""); err != nil {
+		return nil, err
+	}
+	if until, ok := srv.slotCooldownUntil(room, clients); ok {
+		return nil, &SlotCooldownError{Until: until}
+	}
+
+	position, err := srv.nextPosition(ctx, rm)
+	if err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Get Room's Next Position")
+		return nil, err
+	}
+	slot := &Slot{Room: room, Position: position, clients: clients}
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Generate Slot Token")
+		return nil, err
+	}
+	slot.Token = hex.EncodeToString(token)
+	slot.ExpiresAt = time.Now().Add(srv.slotTTL)
+
+	srv.Lock()
+	defer srv.Unlock()
+	for name, other := range srv.slots {
+		if time.Now().After(other.ExpiresAt) {
+			srv.expireSlot(name, other)
+		}
+	}
+	for name, cooldown := range srv.slotCooldowns {
+		if time.Now().After(cooldown.until) {
+			delete(srv.slotCooldowns, name)
+		}
+	}
+	srv.slots[room] = slot
+	reserved := *slot
+	return &reserved, nil
+}
+
+// Returns the Room's Slot if token holds it, a *SlotReservedError if someone else does.
+// An empty token only checks that nobody holds the Room's Slot. Caller must hold the Room's lock.
+func (srv *WordService) checkSlot(room string, token string) (*Slot, error) {
+	srv.Lock()
+	slot := srv.slots[room]
+	if slot != nil && time.Now().After(slot.ExpiresAt) {
+		srv.expireSlot(room, slot)
+		slot = nil
+	}
+	srv.Unlock()
+
+	if slot == nil {
+		if token != "" {
+			return nil, ErrSlotNotFound
+		}
+		return nil, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(slot.Token), []byte(token)) != 1 {
+		return nil, &SlotReservedError{ExpiresAt: slot.ExpiresAt}
+	}
+	return slot, nil
+}
+
+// Makes sure a held Slot's Word lands at the reserved position, caller must hold the Room's lock.
+func (srv *WordService) claimSlot(ctx context.Context, room *Room, slot *Slot) error {
+	position, err := srv.nextPosition(ctx, room)
+	if err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Get Room's Next Position")
+		return err
+	}
+	if position.StoryID != slot.StoryID || position.Version != slot.Version {
+		// Only moderators (or a fork) can change the Story while the Slot is held, the Slot is lost.
+		srv.releaseSlot(room.Name)
+		return ErrSlotConflict
+	}
+	return nil
+}
+
+func (srv *WordService) releaseSlot(room string) {
+	srv.Lock()
+	defer srv.Unlock()
+	delete(srv.slots, room)
+}
+
+// Drops a Slot that expired unused, its clients cool down for ttl. Caller must hold srv's lock.
+func (srv *WordService) expireSlot(room string, slot *Slot) {
+	delete(srv.slots, room)
+	if len(slot.clients) > 0 {
+		srv.slotCooldowns[room] = slotCooldown{clients: slot.clients, until: slot.ExpiresAt.Add(srv.slotTTL)}
+	}
+}
+
+// Until when any of clients cannot reserve the Room's next position, false if they can.
+func (srv *WordService) slotCooldownUntil(room string, clients []string) (time.Time, bool) {
+	srv.Lock()
+	defer srv.Unlock()
+	cooldown, ok := srv.slotCooldowns[room]
+	if !ok || time.Now().After(cooldown.until) {
+		return time.Time{}, false
+	}
+	for _, client := range clients {
+		for _, other := range cooldown.clients {
+			if client != "" && client == other {
+				return cooldown.until, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
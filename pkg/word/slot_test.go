@@ -0,0 +1,94 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveSlot(t *testing.T) {
+	storage := writingStoryStorage()
+	srv := NewWordService(storage, log.New())
+
+	_, err := srv.ReserveSlot(context.Background(), DefaultRoom)
+	require.Equal(t, ErrSlotsDisabled, err)
+
+	srv.EnableSlots(time.Minute)
+	slot, err := srv.ReserveSlot(context.Background(), DefaultRoom)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), slot.StoryID)
+	assert.Equal(t, int32(3), slot.SentenceID)
+	assert.Equal(t, int32(3), slot.Word)
+
+	var reserved *SlotReservedError
+	_, err = srv.ReserveSlot(context.Background(), DefaultRoom)
+	require.True(t, errors.As(err, &reserved))
+	_, err = srv.AddWord(context.Background(), DefaultRoom, "there")
+	require.True(t, errors.As(err, &reserved), "Expected Reserved Position To Be Kept For The Slot")
+	_, err = srv.AddWordWithOptions(context.Background(), DefaultRoom, "there", WordOptions{SlotToken: "wrong"})
+	require.True(t, errors.As(err, &reserved))
+
+	wrdRes, err := srv.AddWordWithOptions(context.Background(), DefaultRoom, "there", WordOptions{SlotToken: slot.Token})
+	require.NoError(t, err)
+	assert.Equal(t, "a time there", wrdRes.Content)
+
+	_, err = srv.AddWordWithOptions(context.Background(), DefaultRoom, "lived", WordOptions{SlotToken: slot.Token})
+	assert.Equal(t, ErrSlotNotFound, err, "Expected Slot To Be Used Once")
+	_, err = srv.AddWord(context.Background(), DefaultRoom, "lived")
+	require.NoError(t, err)
+}
+
+func TestSlotExpiryAndConflict(t *testing.T) {
+	storage := writingStoryStorage()
+	srv := NewWordService(storage, log.New())
+	srv.EnableSlots(time.Millisecond)
+
+	slot, err := srv.ReserveSlot(context.Background(), DefaultRoom)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = srv.AddWord(context.Background(), DefaultRoom, "there")
+	require.NoError(t, err, "Expected Expired Slot To Free The Position")
+	_, err = srv.AddWordWithOptions(context.Background(), DefaultRoom, "lived", WordOptions{SlotToken: slot.Token})
+	assert.Equal(t, ErrSlotNotFound, err)
+
+	srv.EnableSlots(time.Minute)
+	slot, err = srv.ReserveSlot(context.Background(), DefaultRoom)
+	require.NoError(t, err)
+	editStory(t, storage, 1)
+	_, err = srv.AddWordWithOptions(context.Background(), DefaultRoom, "lived", WordOptions{SlotToken: slot.Token})
+	assert.Equal(t, ErrSlotConflict, err)
+	assert.Equal(t, "a time there", sentenceContent(t, storage, 3))
+}
+
+func TestSlotCooldown(t *testing.T) {
+	storage := writingStoryStorage()
+	srv := NewWordService(storage, log.New())
+	srv.EnableSlots(20 * time.Millisecond)
+
+	_, err := srv.ReserveSlot(context.Background(), DefaultRoom, "ip:10.0.0.1", "contributor:alice")
+	require.NoError(t, err)
+	time.Sleep(30 * time.Millisecond)
+
+	var cooldown *SlotCooldownError
+	_, err = srv.ReserveSlot(context.Background(), DefaultRoom, "ip:10.0.0.2", "contributor:alice")
+	require.True(t, errors.As(err, &cooldown), "Expected Client To Wait After Its Slot Expired Unused")
+	_, err = srv.ReserveSlot(context.Background(), DefaultRoom, "ip:10.0.0.1")
+	require.True(t, errors.As(err, &cooldown))
+
+	slot, err := srv.ReserveSlot(context.Background(), DefaultRoom, "ip:10.0.0.3")
+	require.NoError(t, err, "Expected Other Clients To Reserve The Room")
+	_, err = srv.AddWordWithOptions(context.Background(), DefaultRoom, "there", WordOptions{SlotToken: slot.Token})
+	require.NoError(t, err)
+	_, err = srv.ReserveSlot(context.Background(), DefaultRoom, "ip:10.0.0.3")
+	require.NoError(t, err, "Expected A Used Slot Not To Cool Down")
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = srv.ReserveSlot(context.Background(), DefaultRoom, "ip:10.0.0.1")
+	assert.NoError(t, err, "Expected Cooldown To End")
+}
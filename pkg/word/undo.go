@@ -0,0 +1,116 @@
+package word
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+)
+
+var (
+	ErrUndoNotFound = errors.New("Word Not Found Or No Longer The Last Word Of Its Room")
+	ErrUndoExpired  = errors.New("Word Can No Longer Be Undone")
+	ErrUndoToken    = errors.New("Invalid Undo Token")
+	ErrUndoConflict = errors.New("Story Changed Since Word Was Added")
+)
+
+// What a Word changed, so it can be undone. Story and Sentence are as they were before the Word.
+type WordUndo struct {
+	WordID  int32
+	Token   string
+	Room    string
+	AddedAt time.Time
+	StoryID int32
+	Version int32 // Story's Version right after the Word was added.
+
+	Story        *Story    // nil if the Word created the Story.
+	Paragraph    int32     // Paragraph the Word was added to (0 if the Word went to the Title, or created a Paragraph).
+	NewParagraph int32     // Paragraph created by the Word.
+	Sentence     *Sentence // nil if the Word created a Sentence (or went to the Title).
+	NewSentence  int32     // Sentence created by the Word.
+
+	Update *SentenceUpdate // What the Word did to its Sentence (nil if the Word went to the Title).
+}
+
+// Lets the contributor of the last Word of a Room undo it within window (0 disables undo).
+func (srv *WordService) EnableUndo(window time.Duration) {
+	srv.undoWindow = window
+}
+
+// Remembers a just added Word as its Room's last Word (replacing the previous one, which can no longer be undone).
+func (srv *WordService) rememberWord(ctx context.Context, room string, wrdRes *WordResponse, undo *WordUndo) {
+	if srv.undoWindow <= 0 {
+		return
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Generate Undo Token")
+		return
+	}
+
+	srv.Lock()
+	defer srv.Unlock()
+	srv.wordCount++
+	undo.WordID = srv.wordCount
+	undo.Token = hex.EncodeToString(token)
+	undo.Room = room
+	undo.AddedAt = time.Now()
+	srv.lastWords[room] = undo
+
+	wrdRes.WordID = undo.WordID
+	wrdRes.UndoToken = undo.Token
+}
+
+// Undoes the last Word of a Room (if nobody added a Word after it, and the undo window has not passed),
+// reverting any Sentence/Paragraph/Story it finished.
+func (srv *WordService) UndoWord(ctx context.Context, room string, wordId int32, token string) error {
+	if err := ValidateRoomName(room); err != nil {
+		return err
+	}
+
+	lock := srv.roomLock(room)
+	defer lock.Unlock()
+	// No Word can be added to the Room while its last Word is undone.
+	srv.lockRoom(ctx, lock)
+
+	// Undoing would move the Room's reserved position.
+	if _, err := srv.checkSlot(room, ""); err != nil {
+		return err
+	}
+
+	srv.Lock()
+	undo := srv.lastWords[room]
+	srv.Unlock()
+
+	if undo == nil || undo.WordID != wordId {
+		return ErrUndoNotFound
+	}
+	if subtle.ConstantTimeCompare([]byte(undo.Token), []byte(token)) != 1 {
+		return ErrUndoToken
+	}
+	if time.Since(undo.AddedAt) > srv.undoWindow {
+		return ErrUndoExpired
+	}
+
+	if err := srv.storage.UndoWord(ctx, *undo); err != nil {
+		if err != ErrUndoConflict {
+			logging.FromContext(ctx, srv.logger).Error("Could Not Undo Word.")
+		}
+		return err
+	}
+	for _, invalidator := range srv.invalidators {
+		invalidator.InvalidateStory(undo.StoryID)
+	}
+
+	srv.Lock()
+	delete(srv.lastWords, room)
+	srv.Unlock()
+	return nil
+}
@@ -0,0 +1,45 @@
+package word
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndoWord(t *testing.T) {
+	storage := writingStoryStorage()
+	srv := NewWordService(storage, log.New())
+	srv.EnableUndo(time.Minute)
+
+	first, err := srv.AddWord(context.Background(), DefaultRoom, "there")
+	require.NoError(t, err)
+	require.NotEmpty(t, first.UndoToken)
+
+	second, err := srv.AddWord(context.Background(), DefaultRoom, "lived")
+	require.NoError(t, err)
+	assert.Equal(t, first.WordID+1, second.WordID)
+
+	assert.Equal(t, ErrUndoNotFound, srv.UndoWord(context.Background(), DefaultRoom, first.WordID, first.UndoToken), "Expected Only The Last Word To Be Undoable")
+	assert.Equal(t, ErrUndoToken, srv.UndoWord(context.Background(), DefaultRoom, second.WordID, first.UndoToken))
+	assert.Equal(t, ErrUndoNotFound, srv.UndoWord(context.Background(), "other", second.WordID, second.UndoToken))
+
+	require.NoError(t, srv.UndoWord(context.Background(), DefaultRoom, second.WordID, second.UndoToken))
+	assert.Equal(t, "a time there", sentenceContent(t, storage, 3))
+	assert.Equal(t, ErrUndoNotFound, srv.UndoWord(context.Background(), DefaultRoom, second.WordID, second.UndoToken), "Expected Word To Be Undone Once")
+
+	third, err := srv.AddWord(context.Background(), DefaultRoom, "was")
+	require.NoError(t, err)
+	editStory(t, storage, 1)
+	assert.Equal(t, ErrUndoConflict, srv.UndoWord(context.Background(), DefaultRoom, third.WordID, third.UndoToken))
+
+	srv.EnableUndo(time.Nanosecond)
+	fourth, err := srv.AddWord(context.Background(), DefaultRoom, "a")
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, ErrUndoExpired, srv.UndoWord(context.Background(), DefaultRoom, fourth.WordID, fourth.UndoToken))
+}
@@ -0,0 +1,82 @@
+package word
+
+import "unicode"
+
+// Letters that render as blank space, but are not categorized as space or format characters.
+var invisibleLetters = map[rune]bool{
+	'\u115F': true, // Hangul Choseong Filler
+	'\u1160': true, // Hangul Jungseong Filler
+	'\u3164': true, // Hangul Filler
+	'\uFFA0': true, // Halfwidth Hangul Filler
+	'\u2800': true, // Braille Pattern Blank
+}
+
+// Scripts that are commonly mixed within a single word (e.g. Japanese, Korean).
+var compatibleScripts = map[string][]string{
+	"Han":      {"Hiragana", "Katakana", "Hangul"},
+	"Hiragana": {"Han", "Katakana"},
+	"Katakana": {"Han", "Hiragana"},
+	"Hangul":   {"Han"},
+}
+
+// Number of user perceived characters in word (combining marks and joiners extend the previous character).
+func graphemeCount(word string) int {
+	count := 0
+	for _, r := range word {
+		if !unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) {
+			count++
+		}
+	}
+	return count
+}
+
+// True for characters that cannot be seen (or that change how neighbouring characters are shown).
+func isInvisible(r rune) bool {
+	// Graphic covers letters, marks, numbers, punctuation, symbols and spaces,
+	// so anything else is a control, format (e.g. zero-width joiner), private use or unassigned character.
+	return !unicode.IsGraphic(r) || invisibleLetters[r]
+}
+
+func isSpace(r rune) bool {
+	return unicode.IsSpace(r) || unicode.In(r, unicode.Zs, unicode.Zl, unicode.Zp)
+}
+
+// Script of a rune, empty for runes shared by all scripts (digits, punctuation, combining marks).
+func scriptOf(r rune) string {
+	if unicode.In(r, unicode.Common, unicode.Inherited) {
+		return ""
+	}
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// True if word mixes scripts that are not normally mixed, e.g. Latin "a" with Cyrillic "а" (spoofing).
+func isMixedScript(word string) bool {
+	var scripts []string
+	for _, r := range word {
+		script := scriptOf(r)
+		if script == "" || contains(scripts, script) {
+			continue
+		}
+		for _, other := range scripts {
+			if !contains(compatibleScripts[script], other) {
+				return true
+			}
+		}
+		scripts = append(scripts, script)
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
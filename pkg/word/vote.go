@@ -0,0 +1,293 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+)
+
+// Maximum length of a voter's ID.
+const MaxVoterLength = 64
+
+var (
+	ErrRoundNotFound     = errors.New("Voting Round Not Found")
+	ErrCandidateNotFound = errors.New("Candidate Word Not Found")
+	ErrRoundClosed       = errors.New("Voting Round Is Closed")
+	ErrAlreadyVoted      = errors.New("Already Voted In This Round")
+	ErrInvalidVoter      = errors.New("Invalid Voter")
+	ErrVotingBatch       = errors.New("Batches Cannot Be Added To A Room That Votes On Words")
+)
+
+// A Word submitted for the next slot of a Room's Story.
+type Candidate struct {
+	ID        int32     `json:"id"`
+	Round     int32     `json:"round"`
+	Word      string    `json:"word"`
+	Votes     int32     `json:"votes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Candidates voted on for the next slot of a Room's Story, the winner is added when the round ends.
+type VoteRound struct {
+	ID         int32       `json:"id"`
+	Room       string      `json:"room"`
+	IsClosed   bool        `json:"is_closed"`
+	Winner     int32       `json:"winner,omitempty"` // Winning Candidate's ID.
+	Story      int32       `json:"story,omitempty"`  // Story the winning Word was added to.
+	EndsAt     time.Time   `json:"ends_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Candidates []Candidate `json:"candidates"` // in order of submission
+}
+
+type VoteRequest struct {
+	CandidateID int32  `json:"candidate_id"`
+	Voter       string `json:"voter"`
+}
+
+type VoteStorage interface {
+	// Returns ErrRoundNotFound if Room has no open round.
+	GetOpenRound(ctx context.Context, room string) (*VoteRound, error)
+	AddRound(ctx context.Context, room string, endsAt time.Time) (int32, error)
+	// Returns ErrRoundNotFound if there is no such round.
+	GetRound(ctx context.Context, roundId int32) (*VoteRound, error)
+	// Open rounds that ended before now.
+	GetDueRounds(ctx context.Context, now time.Time) ([]VoteRound, error)
+	AddCandidate(ctx context.Context, roundId int32, word string) (int32, error)
+	// Returns ErrAlreadyVoted if voter already voted in the round.
+	AddVote(ctx context.Context, roundId int32, candidateId int32, voter string) error
+	CloseRound(ctx context.Context, roundId int32, winner int32, storyId int32) error
+}
+
+// Lets Rooms with StoryRules.VoteSeconds choose their next Word by vote.
+func (srv *WordService) EnableVoting(storage VoteStorage) {
+	srv.votes = storage
+}
+
+// Submits a Word as a candidate in the Room's open round (starting one if there is none).
+// Caller must hold the Room's lock.
+func (srv *WordService) addCandidate(ctx context.Context, room *Room, word string) (*WordResponse, error) {
+	round, err := srv.votes.GetOpenRound(ctx, room.Name)
+	if err == nil && !time.Now().Before(round.EndsAt) {
+		// Round is over (but not closed yet), its winner goes before this Word.
+		if err := srv.closeRound(ctx, round); err != nil {
+			return nil, err
+		}
+		err = ErrRoundNotFound
+	}
+	if err == ErrRoundNotFound {
+		endsAt := time.Now().Add(time.Duration(room.Rules.VoteSeconds) * time.Second)
+		roundId, err := srv.votes.AddRound(ctx, room.Name, endsAt)
+		if err != nil {
+			logging.FromContext(ctx, srv.logger).Error("Could Not Start Voting Round.")
+			return nil, err
+		}
+		logging.FromContext(ctx, srv.logger).Info("Voting Round ", roundId, " Started In Room ", room.Name)
+		round = &VoteRound{ID: roundId, Room: room.Name, EndsAt: endsAt}
+	} else if err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Get Voting Round.")
+		return nil, err
+	}
+
+	candidate := Candidate{Round: round.ID, Word: word}
+	for _, c := range round.Candidates {
+		if c.Word == word {
+			// Same Word was already submitted, it is the same candidate.
+			candidate = c
+		}
+	}
+	if candidate.ID == 0 {
+		if candidate.ID, err = srv.votes.AddCandidate(ctx, round.ID, word); err != nil {
+			logging.FromContext(ctx, srv.logger).Error("Could Not Add Candidate Word.")
+			return nil, err
+		}
+		candidate.CreatedAt = time.Now()
+	}
+
+	story, err := srv.storage.GetUnfinishedStory(ctx, room.Name)
+	wrdRes := WordResponse{Room: room.Name, Candidate: &candidate, RoundEndsAt: &round.EndsAt}
+	if err == nil {
+		wrdRes.ID = story.ID
+		wrdRes.Title = story.Title
+		wrdRes.Prompt = story.Prompt
+	}
+	return &wrdRes, nil
+}
+
+// Votes for a candidate Word of an open round.
+func (srv *WordService) Vote(ctx context.Context, roundId int32, vote VoteRequest) (*VoteRound, error) {
+	if len(vote.Voter) == 0 || len(vote.Voter) > MaxVoterLength {
+		return nil, ErrInvalidVoter
+	}
+
+	round, err := srv.GetRound(ctx, roundId)
+	if err != nil {
+		return nil, err
+	}
+	if round.IsClosed || !time.Now().Before(round.EndsAt) {
+		return nil, ErrRoundClosed
+	}
+	found := false
+	for _, candidate := range round.Candidates {
+		found = found || candidate.ID == vote.CandidateID
+	}
+	if !found {
+		return nil, ErrCandidateNotFound
+	}
+
+	if err := srv.votes.AddVote(ctx, roundId, vote.CandidateID, vote.Voter); err != nil {
+		if err != ErrAlreadyVoted {
+			logging.FromContext(ctx, srv.logger).Error("Could Not Add Vote.")
+		}
+		return nil, err
+	}
+	return srv.GetRound(ctx, roundId)
+}
+
+// A round with its candidates and their votes.
+func (srv *WordService) GetRound(ctx context.Context, roundId int32) (*VoteRound, error) {
+	if srv.votes == nil {
+		return nil, ErrRoundNotFound
+	}
+	return srv.votes.GetRound(ctx, roundId)
+}
+
+// The open round of a Room.
+func (srv *WordService) GetOpenRound(ctx context.Context, room string) (*VoteRound, error) {
+	if err := ValidateRoomName(room); err != nil {
+		return nil, err
+	}
+	if srv.votes == nil {
+		return nil, ErrRoundNotFound
+	}
+	return srv.votes.GetOpenRound(ctx, room)
+}
+
+// Closes ended rounds (of all Rooms), adding their winning Words. Returns the closed rounds.
+// A round that fails to close stays open (to be retried), the others are still closed and the failures
+// are returned as a *SweepError.
+func (srv *WordService) CloseDueRounds(ctx context.Context) ([]VoteRound, error) {
+	rounds, err := srv.votes.GetDueRounds(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var closed []VoteRound
+	var errs []error
+	for _, round := range rounds {
+		if r, err := srv.closeDueRound(ctx, round); err != nil {
+			logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Close Voting Round ", round.ID)
+			errs = append(errs, fmt.Errorf("Round %d: %w", round.ID, err))
+		} else if r != nil {
+			closed = append(closed, *r)
+		}
+	}
+	return closed, sweepError(errs)
+}
+
+func (srv *WordService) closeDueRound(ctx context.Context, round VoteRound) (*VoteRound, error) {
+	lock := srv.roomLock(round.Room)
+	defer lock.Unlock()
+	srv.lockRoom(ctx, lock)
+
+	// Round may have been closed (by a new candidate) before the lock was acquired.
+	current, err := srv.votes.GetRound(ctx, round.ID)
+	if err != nil || current.IsClosed {
+		return nil, nil
+	}
+	if err := srv.closeRound(ctx, current); err != nil {
+		return nil, err
+	}
+	return srv.votes.GetRound(ctx, round.ID)
+}
+
+// Adds the Word with most votes (earliest submitted wins a tie) and closes the round.
+// Caller must hold the lock of round's Room.
+func (srv *WordService) closeRound(ctx context.Context, round *VoteRound) error {
+	var winner *Candidate
+	for i, candidate := range round.Candidates {
+		if winner == nil || candidate.Votes > winner.Votes {
+			winner = &round.Candidates[i]
+		}
+	}
+
+	var winnerId, storyId int32
+	if winner != nil {
+		rm, err := srv.getRoom(ctx, round.Room)
+		if err != nil {
+			return err
+		}
+		if wrdRes, err := srv.addWord(ctx, rm, winner.Word); err != nil {
+			if !wordNotAllowed(err) {
+				// Round stays open, so it is retried (e.g. once the DB is back).
+				logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Add Winning Word Of Round ", round.ID)
+				return err
+			}
+			// Closed without a winner, a Word that is not allowed here won't be retried forever.
+			logging.FromContext(ctx, srv.logger).WithError(err).Info("Winning Word Of Round ", round.ID, " Not Allowed")
+		} else {
+			winnerId, storyId = winner.ID, wrdRes.ID
+		}
+	}
+
+	if err := srv.votes.CloseRound(ctx, round.ID, winnerId, storyId); err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Close Voting Round.")
+		return err
+	}
+	logging.FromContext(ctx, srv.logger).Info("Voting Round ", round.ID, " Closed In Room ", round.Room)
+	return nil
+}
+
+// Is err a rejection of the Word itself (by moderation, or where it would go), rather than a storage error ?
+func wordNotAllowed(err error) bool {
+	var rejected *moderation.RejectedError
+	return errors.As(err, &rejected) || errors.Is(err, ErrPunctuationOnly)
+}
+
+// Periodically closes ended voting rounds, adding their winning Words.
+type VoteCloser struct {
+	service  *WordService
+	interval time.Duration
+	logger   *log.Logger
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewVoteCloser(service *WordService, interval time.Duration, logger *log.Logger) *VoteCloser {
+	closer := new(VoteCloser)
+	closer.service = service
+	closer.interval = interval
+	closer.logger = logger
+	closer.stop = make(chan struct{})
+	closer.done = make(chan struct{})
+	return closer
+}
+
+func (c *VoteCloser) Start() {
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := c.service.CloseDueRounds(context.Background()); err != nil {
+					c.logger.WithError(err).Error("Could Not Close Voting Rounds")
+				}
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stops closing rounds, waiting for a check in progress to complete.
+func (c *VoteCloser) Stop() {
+	close(c.stop)
+	<-c.done
+}
@@ -0,0 +1,249 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/shubhamdwivedii/collab-story/pkg/paragraph"
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A writingStoryStorage whose default Room votes on Words.
+func votingRoomStorage() *MemoryStorage {
+	storage := writingStoryStorage()
+	rules := DefaultStoryRules
+	rules.VoteSeconds = 60
+	storage.SaveRoom(context.Background(), Room{Name: DefaultRoom, Rules: rules})
+	return storage
+}
+
+type memoryVoteStorage struct {
+	rounds map[int32]*VoteRound
+	voters map[int32]map[string]int32 // round's voters and their candidates
+}
+
+func newMemoryVoteStorage() *memoryVoteStorage {
+	return &memoryVoteStorage{rounds: make(map[int32]*VoteRound), voters: make(map[int32]map[string]int32)}
+}
+
+func (s *memoryVoteStorage) GetOpenRound(ctx context.Context, room string) (*VoteRound, error) {
+	for id, round := range s.rounds {
+		if round.Room == room && !round.IsClosed {
+			return s.GetRound(ctx, id)
+		}
+	}
+	return nil, ErrRoundNotFound
+}
+
+func (s *memoryVoteStorage) AddRound(ctx context.Context, room string, endsAt time.Time) (int32, error) {
+	id := int32(len(s.rounds) + 1)
+	s.rounds[id] = &VoteRound{ID: id, Room: room, EndsAt: endsAt}
+	s.voters[id] = make(map[string]int32)
+	return id, nil
+}
+
+func (s *memoryVoteStorage) GetRound(ctx context.Context, roundId int32) (*VoteRound, error) {
+	round, ok := s.rounds[roundId]
+	if !ok {
+		return nil, ErrRoundNotFound
+	}
+	r := *round
+	r.Candidates = nil
+	for _, candidate := range round.Candidates {
+		for _, voted := range s.voters[roundId] {
+			if voted == candidate.ID {
+				candidate.Votes++
+			}
+		}
+		r.Candidates = append(r.Candidates, candidate)
+	}
+	return &r, nil
+}
+
+func (s *memoryVoteStorage) GetDueRounds(ctx context.Context, now time.Time) ([]VoteRound, error) {
+	var rounds []VoteRound
+	for _, round := range s.rounds {
+		if !round.IsClosed && !round.EndsAt.After(now) {
+			rounds = append(rounds, *round)
+		}
+	}
+	return rounds, nil
+}
+
+func (s *memoryVoteStorage) AddCandidate(ctx context.Context, roundId int32, word string) (int32, error) {
+	round := s.rounds[roundId]
+	id := roundId*100 + int32(len(round.Candidates)) + 1
+	round.Candidates = append(round.Candidates, Candidate{ID: id, Round: roundId, Word: word})
+	return id, nil
+}
+
+func (s *memoryVoteStorage) AddVote(ctx context.Context, roundId int32, candidateId int32, voter string) error {
+	if _, ok := s.voters[roundId][voter]; ok {
+		return ErrAlreadyVoted
+	}
+	s.voters[roundId][voter] = candidateId
+	return nil
+}
+
+func (s *memoryVoteStorage) CloseRound(ctx context.Context, roundId int32, winner int32, storyId int32) error {
+	round := s.rounds[roundId]
+	round.IsClosed, round.Winner, round.Story = true, winner, storyId
+	return nil
+}
+
+func TestVoteOnWords(t *testing.T) {
+	storage := votingRoomStorage()
+	votes := newMemoryVoteStorage()
+	srv := NewWordService(storage, log.New())
+	srv.EnableVoting(votes)
+
+	first, err := srv.AddWord(context.Background(), DefaultRoom, "there")
+	require.NoError(t, err)
+	require.NotNil(t, first.Candidate)
+	second, err := srv.AddWord(context.Background(), DefaultRoom, "a")
+	require.NoError(t, err)
+	again, err := srv.AddWord(context.Background(), DefaultRoom, "a")
+	require.NoError(t, err)
+	assert.Equal(t, second.Candidate.ID, again.Candidate.ID, "Expected Same Word To Be The Same Candidate")
+	assert.Equal(t, "a time", sentenceContent(t, storage, 3), "Expected Candidates Not To Be Added")
+
+	_, err = srv.AddWords(context.Background(), DefaultRoom, []string{"batch"})
+	assert.Equal(t, ErrVotingBatch, err)
+
+	roundId := first.Candidate.Round
+	_, err = srv.Vote(context.Background(), roundId, VoteRequest{CandidateID: second.Candidate.ID, Voter: "alice"})
+	require.NoError(t, err)
+	_, err = srv.Vote(context.Background(), roundId, VoteRequest{CandidateID: first.Candidate.ID, Voter: "alice"})
+	assert.Equal(t, ErrAlreadyVoted, err)
+	_, err = srv.Vote(context.Background(), roundId, VoteRequest{CandidateID: 999, Voter: "bob"})
+	assert.Equal(t, ErrCandidateNotFound, err)
+	_, err = srv.Vote(context.Background(), roundId, VoteRequest{CandidateID: first.Candidate.ID})
+	assert.Equal(t, ErrInvalidVoter, err)
+
+	closed, err := srv.CloseDueRounds(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, closed, "Expected Round Not To Close Before It Ends")
+
+	votes.rounds[roundId].EndsAt = time.Now().Add(-time.Second)
+	_, err = srv.Vote(context.Background(), roundId, VoteRequest{CandidateID: first.Candidate.ID, Voter: "bob"})
+	assert.Equal(t, ErrRoundClosed, err)
+
+	closed, err = srv.CloseDueRounds(context.Background())
+	require.NoError(t, err)
+	require.Len(t, closed, 1)
+	assert.Equal(t, second.Candidate.ID, closed[0].Winner)
+	assert.Equal(t, "a time a", sentenceContent(t, storage, 3))
+
+	// Next candidate starts a new round.
+	next, err := srv.AddWord(context.Background(), DefaultRoom, "dragon")
+	require.NoError(t, err)
+	assert.NotEqual(t, roundId, next.Candidate.Round)
+}
+
+func TestVoteTieGoesToEarliestCandidate(t *testing.T) {
+	storage := votingRoomStorage()
+	votes := newMemoryVoteStorage()
+	srv := NewWordService(storage, log.New())
+	srv.EnableVoting(votes)
+
+	first, err := srv.AddWord(context.Background(), DefaultRoom, "there")
+	require.NoError(t, err)
+	_, err = srv.AddWord(context.Background(), DefaultRoom, "here")
+	require.NoError(t, err)
+
+	// A new candidate after the round ended closes it first.
+	votes.rounds[first.Candidate.Round].EndsAt = time.Now()
+	_, err = srv.AddWord(context.Background(), DefaultRoom, "long")
+	require.NoError(t, err)
+	assert.Equal(t, "a time there", sentenceContent(t, storage, 3))
+	assert.True(t, votes.rounds[first.Candidate.Round].IsClosed)
+}
+
+// Fails to add Words to the Sentence failing (0 for none).
+type failingSentenceStorage struct {
+	*MemoryStorage
+	failing int32
+}
+
+func (s *failingSentenceStorage) UpdateSentence(ctx context.Context, sentenceId int32, word string, rules StoryRules) (*SentenceUpdate, error) {
+	if sentenceId == s.failing {
+		return nil, errors.New("Error Updating Sentence In DB")
+	}
+	return s.MemoryStorage.UpdateSentence(ctx, sentenceId, word, rules)
+}
+
+func TestVoteRoundStaysOpenOnStorageError(t *testing.T) {
+	storage := &failingSentenceStorage{MemoryStorage: votingRoomStorage(), failing: 3}
+	votes := newMemoryVoteStorage()
+	srv := NewWordService(storage, log.New())
+	srv.EnableVoting(votes)
+
+	wrdRes, err := srv.AddWord(context.Background(), DefaultRoom, "there")
+	require.NoError(t, err)
+	round := votes.rounds[wrdRes.Candidate.Round]
+	round.EndsAt = time.Now()
+
+	_, err = srv.CloseDueRounds(context.Background())
+	assert.Error(t, err)
+	assert.False(t, round.IsClosed, "Expected Round To Be Retried")
+
+	storage.failing = 0
+	closed, err := srv.CloseDueRounds(context.Background())
+	require.NoError(t, err)
+	require.Len(t, closed, 1)
+	assert.Equal(t, wrdRes.Candidate.ID, closed[0].Winner)
+	assert.Equal(t, "a time there", sentenceContent(t, storage.MemoryStorage, 3))
+}
+
+func TestVoteRoundFailureDoesNotBlockOtherRooms(t *testing.T) {
+	storage := &failingSentenceStorage{MemoryStorage: votingRoomStorage(), failing: 3}
+	rules := DefaultStoryRules
+	rules.VoteSeconds = 60
+	storage.SaveRoom(context.Background(), Room{Name: "other", Rules: rules})
+	storage.SaveStory(Story{ID: 10, Room: "other", Title: "Far away", TitleAdded: true, State: StateWriting})
+	storage.SaveParagraph(Paragraph{ID: 11, Story: 10})
+	storage.SaveSentence(Sentence{ID: 12, Paragraph: 11, Content: "there lived"})
+	votes := newMemoryVoteStorage()
+	srv := NewWordService(storage, log.New())
+	srv.EnableVoting(votes)
+
+	failing, err := srv.AddWord(context.Background(), DefaultRoom, "there")
+	require.NoError(t, err)
+	other, err := srv.AddWord(context.Background(), "other", "a")
+	require.NoError(t, err)
+	votes.rounds[failing.Candidate.Round].EndsAt = time.Now()
+	votes.rounds[other.Candidate.Round].EndsAt = time.Now()
+
+	closed, err := srv.CloseDueRounds(context.Background())
+	var sweepErr *SweepError
+	require.ErrorAs(t, err, &sweepErr)
+	assert.Len(t, sweepErr.Errors, 1)
+	require.Len(t, closed, 1, "Expected Other Room's Round To Close")
+	assert.Equal(t, other.Candidate.Round, closed[0].ID)
+	assert.Equal(t, "there lived a", sentenceContent(t, storage.MemoryStorage, 12))
+	assert.False(t, votes.rounds[failing.Candidate.Round].IsClosed)
+}
+
+func TestVoteRoundClosesWithoutWinnerNotAllowed(t *testing.T) {
+	storage := votingRoomStorage()
+	votes := newMemoryVoteStorage()
+	srv := NewWordService(storage, log.New())
+	srv.EnableVoting(votes)
+
+	wrdRes, err := srv.AddWord(context.Background(), DefaultRoom, "!")
+	require.NoError(t, err)
+	votes.rounds[wrdRes.Candidate.Round].EndsAt = time.Now()
+	// Sentence was finished (by a moderator) since, so "!" would start a new one.
+	storage.SaveSentence(Sentence{ID: 3, Paragraph: 2, Content: "a time.", IsFinished: true})
+
+	closed, err := srv.CloseDueRounds(context.Background())
+	require.NoError(t, err)
+	require.Len(t, closed, 1)
+	assert.Zero(t, closed[0].Winner)
+}
@@ -1,207 +1,623 @@
 package word
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"regexp"
+	"fmt"
+	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shubhamdwivedii/collab-story/pkg/logging"
+	"github.com/shubhamdwivedii/collab-story/pkg/moderation"
 	. "github.com/shubhamdwivedii/collab-story/pkg/paragraph"
 	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
 	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/text/unicode/norm"
 )
 
 type WordRequest struct {
 	Word string `json:"word"`
+	// Word is only added if it would still land here (e.g. the next_position of the last WordResponse).
+	ExpectedPosition *Position `json:"expected_position,omitempty"`
 }
 
 type WordResponse struct {
 	ID      int32  `json:"id"`
+	Room    string `json:"room"`
 	Title   string `json:"title"`
 	Content string `json:"current_sentence"`
+	Prompt  string `json:"prompt,omitempty"` // Story's theme
+	// Where the next Word will land, to be sent back as expected_position.
+	NextPosition *Position `json:"next_position,omitempty"`
+	// Sent (as Undo-Token header) with DELETE /add/{word_id} to undo the Word (if undo is enabled).
+	WordID    int32  `json:"word_id,omitempty"`
+	UndoToken string `json:"undo_token,omitempty"`
+	// Set instead (in a Room that votes on Words) when Word was submitted as a candidate for the next slot.
+	Candidate   *Candidate `json:"candidate,omitempty"`
+	RoundEndsAt *time.Time `json:"round_ends_at,omitempty"`
 }
 
 type WordError struct {
 	Error string `json:"error"`
+	Code  string `json:"code,omitempty"` // e.g. moderation.RejectedCode
+	// Room's current state, if Word was not added because its expected position no longer matches.
+	Current *WordResponse `json:"current,omitempty"`
+}
+
+type WordBatchRequest struct {
+	Words []string `json:"words"`
+}
+
+type WordBatchResponse struct {
+	Results []WordResponse `json:"results"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// Maximum number of Words accepted in a single batch.
+const MaxBatchSize = 100
+
+// Maximum length of an Idempotency-Key.
+const MaxIdempotencyKeyLength = 255
+
+var (
+	ErrIdempotencyKeyNotFound = errors.New("Idempotency Key Not Found")
+	ErrIdempotencyKeyReused   = errors.New("Idempotency Key Was Already Used With A Different Request")
+)
+
+// A WordResponse stored against an Idempotency-Key (per Room), replayed for retries of the same request.
+type IdempotentResponse struct {
+	Room        string       `json:"room"`
+	Key         string       `json:"key"`
+	RequestHash string       `json:"request_hash"` // of the request body (HashRequest)
+	Response    WordResponse `json:"response"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// Hex encoded SHA-256 of a request body, a retry must have the same body to be replayed.
+func HashRequest(body []byte) string {
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
+
+type IdempotencyStorage interface {
+	// Returns ErrIdempotencyKeyNotFound if key was not stored for room (or was stored before since).
+	GetIdempotentResponse(ctx context.Context, room string, key string, since time.Time) (*IdempotentResponse, error)
+	SaveIdempotentResponse(ctx context.Context, idemRes IdempotentResponse) error
 }
 
 type WordStorage interface {
-	GetUnfinishedStory() (*Story, error)
-	AddStory() (int32, error)
-	GetStory(storyId int32) (*Story, error)
-	UpdateStoryTitle(storyId int32, word string) error
+	// Returns ErrStoryNotFound if Room has no Story in progress.
+	GetUnfinishedStory(ctx context.Context, room string) (*Story, error)
+	GetUnfinishedStories(ctx context.Context) ([]Story, error)
+	AddStory(ctx context.Context, room string, prompt Prompt) (int32, error)
+	GetStory(ctx context.Context, storyId int32) (*Story, error)
+	// Returns ErrPunctuationOnly if word is punctuation and the Title is empty.
+	UpdateStoryTitle(ctx context.Context, storyId int32, word string, rules StoryRules) error
+
+	// Returns ErrParagraphNotFound if all of Story's Paragraphs are finished.
+	GetUnfinishedParagraph(ctx context.Context, storyId int32) (*Paragraph, error)
+	AddParagraph(ctx context.Context, storyId int32) (int32, error)
+
+	// Returns ErrSentenceNotFound if all of Paragraph's Sentences are finished.
+	GetUnfinishedSentence(ctx context.Context, paragraphId int32) (*Sentence, error)
+	// Adds a Sentence starting with word. Returns ErrPunctuationOnly if word is punctuation.
+	AddSentence(ctx context.Context, paragraphId int32, word string, rules StoryRules) (*SentenceUpdate, error)
+	GetSentence(ctx context.Context, sentenceId int32) (*Sentence, error)
+	// Adds word to a Sentence. Sentence (and its Paragraph and Story) are finished as per rules.
+	UpdateSentence(ctx context.Context, sentenceId int32, word string, rules StoryRules) (*SentenceUpdate, error)
+
+	UpdateStoryState(ctx context.Context, storyId int32, state State) error
+	// Returns ErrUndoConflict if Story's Version is not undo.Version anymore.
+	UndoWord(ctx context.Context, undo WordUndo) error
+
+	// Moderation
+	GetParagraph(ctx context.Context, paragraphId int32) (*Paragraph, error)
+	GetStorySentences(ctx context.Context, storyId int32) ([]Sentence, error)
+	EditSentence(ctx context.Context, sentenceId int32, content string, finish bool, rules StoryRules) error
+	RenameStory(ctx context.Context, storyId int32, title string, rules StoryRules) error
 
-	GetUnfinishedParagraph(storyId int32) (*Paragraph, error)
-	AddParagraph(storyId int32) (int32, error)
+	GetRoom(ctx context.Context, name string) (*Room, error)
+	SaveRoom(ctx context.Context, room Room) error
+	// Sets (or clears, if nil) the prompt of a Room's next Story, creating the Room with rules if needed.
+	SaveRoomPrompt(ctx context.Context, room string, rules StoryRules, prompt *Prompt) error
 
-	GetUnfinishedSentence(paragraphId int32) (*Sentence, error)
-	AddSentence(paragraphId int32, word string) (int32, error)
-	GetSentence(sentenceId int32) (*Sentence, error)
-	UpdateSentence(sentenceId int32, word string) error
+	// Returns ErrInvalidForkPosition if parent has no such Paragraph/Sentence.
+	ForkStory(ctx context.Context, parentId int32, paragraphIdx int32, sentenceIdx int32, room string, rules StoryRules) (int32, error)
+}
+
+// What adding a Word to a Sentence did, as reported by WordStorage.
+type SentenceUpdate struct {
+	Sentence          Sentence // with the Word (and finished if the Word finished it)
+	ParagraphFinished bool
+	StoryFinished     bool
+	Version           int32 // Story's Version after the Word
+}
+
+// Notified whenever a Word changes a Story (e.g. to drop it from a Cache).
+type StoryInvalidator interface {
+	InvalidateStory(storyId int32)
+}
+
+// Errors of the items a sweep (e.g. CloseDueRounds) failed on, the other items were still processed.
+type SweepError struct {
+	Errors []error
+}
+
+func (e *SweepError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *SweepError) Unwrap() []error {
+	return e.Errors
+}
+
+// Errors as a *SweepError, nil if there are none.
+func sweepError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &SweepError{Errors: errs}
 }
 
 type WordService struct {
-	storage WordStorage
-	logger  *log.Logger
-	sync.Mutex
+	storage           WordStorage
+	idempotency       IdempotencyStorage
+	idempotencyWindow time.Duration
+	invalidators      []StoryInvalidator
+	filter            moderation.WordFilter
+	rejections        moderation.RejectionStorage
+	audit             moderation.AuditStorage
+	votes             VoteStorage
+	slotTTL           time.Duration
+	wordsAdded        prometheus.Counter
+	partsFinished     *prometheus.CounterVec
+	lockWait          prometheus.Histogram
+	slots             map[string]*Slot        // by Room
+	slotCooldowns     map[string]slotCooldown // by Room
+	prompts           []Prompt                // rotated through for new Stories
+	promptIndex       int
+	roomLocks         [roomLockShards]sync.Mutex
+	lockWaiters       map[int64]time.Time // since when, by waiter
+	nextWaiter        int64
+	undoWindow        time.Duration
+	defaultRules      StoryRules           // of Rooms that were never configured
+	lastWords         map[string]*WordUndo // by Room
+	wordCount         int32
+	logger            *log.Logger
+	sync.Mutex        // guards lockWaiters, lastWords, wordCount, prompts, slots and slotCooldowns
 }
 
 func NewWordService(storage WordStorage, logger *log.Logger) *WordService {
 	wrdsrv := new(WordService)
 	wrdsrv.storage = storage
+	wrdsrv.lockWaiters = make(map[int64]time.Time)
+	wrdsrv.lastWords = make(map[string]*WordUndo)
+	wrdsrv.slots = make(map[string]*Slot)
+	wrdsrv.slotCooldowns = make(map[string]slotCooldown)
+	wrdsrv.defaultRules = DefaultStoryRules
 	wrdsrv.logger = logger
 	return wrdsrv
 }
 
+// Enables replaying responses for retried requests with the same Idempotency-Key within window.
+func (srv *WordService) EnableIdempotency(storage IdempotencyStorage, window time.Duration) {
+	srv.idempotency = storage
+	srv.idempotencyWindow = window
+}
+
+// Registers an invalidator to be notified of every Story a Word is added to.
+func (srv *WordService) AddInvalidator(invalidator StoryInvalidator) {
+	srv.invalidators = append(srv.invalidators, invalidator)
+}
+
+// Maximum length of a Word in characters (not counting up to MaxPunctuationLength trailing punctuation).
+// A character may be made of several runes (e.g. a letter with combining marks), up to MaxWordRunes in all.
+const (
+	MaxWordLength        = 16
+	MaxWordRunes         = 32
+	MaxPunctuationLength = 3
+)
+
+// Validates a Word as it will be stored (NFC normalized).
 func ValidateWord(word string) error {
-	if len(word) < 1 || len(word) > 16 {
+	if !utf8.ValidString(word) {
+		return errors.New("Invalid Word Encoding")
+	}
+	word = norm.NFC.String(word)
+
+	core := TrimPunctuation(word)
+	if len(word) < 1 || graphemeCount(core) > MaxWordLength || utf8.RuneCountInString(core) > MaxWordRunes ||
+		len(word)-len(core) > MaxPunctuationLength {
 		return errors.New("Invalid Word Length")
-	} else {
-		re, _ := regexp.Compile("^\\S+$")
-		if re.MatchString(word) {
-			// word contains no spaces
-			return nil
-		} else {
+	}
+
+	for _, r := range word {
+		if isSpace(r) {
 			return errors.New("Multiple Words Sent")
 		}
+		if isInvisible(r) {
+			return errors.New("Word Contains Invisible Or Control Characters")
+		}
+	}
+
+	if isMixedScript(core) {
+		return errors.New("Word Mixes Characters From Different Scripts")
+	}
+	return nil
+}
+
+// This will add a Word to a Room's Story/Paragraph/Sentence in Storage
+func (srv *WordService) AddWord(ctx context.Context, room string, word string) (*WordResponse, error) {
+	return srv.AddWordWithOptions(ctx, room, word, WordOptions{})
+}
+
+// Same as AddWord, but the Word only lands where opts say (in a reserved Slot, or at an expected position).
+// Without a SlotToken the Word is not added while the next position is reserved.
+func (srv *WordService) AddWordWithOptions(ctx context.Context, room string, word string, opts WordOptions) (*WordResponse, error) {
+	ctx, span := tracing.Start(ctx, "WordService.AddWord")
+	defer span.End()
+	span.SetAttributes(attribute.String("room", room))
+
+	if err := ValidateRoomName(room); err != nil {
+		return nil, err
 	}
+	if err := ValidateWord(word); err != nil {
+		logging.FromContext(ctx, srv.logger).Error("Word is invalid.")
+		return nil, err
+	}
+	if err := srv.moderateWord(ctx, room, word); err != nil {
+		return nil, err
+	}
+
+	lock := srv.roomLock(room)
+	defer lock.Unlock()
+	// Adding two words concurrently (to the same Room) might lead to inconsistency
+	srv.lockRoom(ctx, lock)
+
+	slot, err := srv.checkSlot(room, opts.SlotToken)
+	if err != nil {
+		return nil, err
+	}
+	rm, err := srv.getRoom(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	return srv.submitWordWithOptions(ctx, rm, slot, opts, word)
 }
 
-// This will add a Word to a Story/Paragraph/Sentence in Storage
-func (srv *WordService) AddWord(word string) (*WordResponse, error) {
+// Same as AddWordWithOptions, but a retry with the same key and requestHash (to the same Room, within the
+// idempotency window) replays the first response instead of adding the Word again. Returns true if response
+// was replayed, and ErrIdempotencyKeyReused if key was used for a different request.
+func (srv *WordService) AddWordWithKey(ctx context.Context, room string, key string, requestHash string, word string, opts WordOptions) (*WordResponse, bool, error) {
+	ctx, span := tracing.Start(ctx, "WordService.AddWordWithKey")
+	defer span.End()
+	span.SetAttributes(attribute.String("room", room))
+
+	if srv.idempotency == nil {
+		wrdRes, err := srv.AddWordWithOptions(ctx, room, word, opts)
+		return wrdRes, false, err
+	}
+
+	if len(key) == 0 || len(key) > MaxIdempotencyKeyLength {
+		return nil, false, errors.New("Invalid Idempotency Key Length")
+	}
+
+	if err := ValidateRoomName(room); err != nil {
+		return nil, false, err
+	}
 	if err := ValidateWord(word); err != nil {
-		srv.logger.Error("Word is invalid.")
+		logging.FromContext(ctx, srv.logger).Error("Word is invalid.")
+		return nil, false, err
+	}
+	if err := srv.moderateWord(ctx, room, word); err != nil {
+		return nil, false, err
+	}
+
+	lock := srv.roomLock(room)
+	defer lock.Unlock()
+	// Lookup and Add happen under the same lock, so simultaneous duplicates (to a Room) are serialized.
+	srv.lockRoom(ctx, lock)
+
+	since := time.Now().Add(-srv.idempotencyWindow)
+	idemRes, err := srv.idempotency.GetIdempotentResponse(ctx, room, key, since)
+	if err == nil {
+		if idemRes.RequestHash != requestHash {
+			return nil, false, ErrIdempotencyKeyReused
+		}
+		logging.FromContext(ctx, srv.logger).Info("Replaying Response For Idempotency Key: " + key)
+		return &idemRes.Response, true, nil
+	} else if err != ErrIdempotencyKeyNotFound {
+		logging.FromContext(ctx, srv.logger).Error("Could Not Lookup Idempotency Key.")
+		return nil, false, err
+	}
+
+	// Checked after the lookup, so a retry is replayed even though its Slot was used (or its position passed).
+	slot, err := srv.checkSlot(room, opts.SlotToken)
+	if err != nil {
+		return nil, false, err
+	}
+	rm, err := srv.getRoom(ctx, room)
+	if err != nil {
+		return nil, false, err
+	}
+
+	wrdRes, err := srv.submitWordWithOptions(ctx, rm, slot, opts, word)
+	if err != nil {
+		return nil, false, err
+	}
+
+	idemRes = &IdempotentResponse{
+		Room:        room,
+		Key:         key,
+		RequestHash: requestHash,
+		Response:    *wrdRes,
+		CreatedAt:   time.Now(),
+	}
+	if err := srv.idempotency.SaveIdempotentResponse(ctx, *idemRes); err != nil {
+		// Word is already added, a failure here only means a retry won't be replayed.
+		logging.FromContext(ctx, srv.logger).WithError(err).Error("Could Not Save Idempotency Key")
+	}
+	return wrdRes, false, nil
+}
+
+// This will add multiple Words (in order) to a Room's Stories/Paragraphs/Sentences in Storage.
+// All Words are validated before any of them is added, and the Room's lock is held for the whole batch.
+func (srv *WordService) AddWords(ctx context.Context, room string, words []string) ([]WordResponse, error) {
+	ctx, span := tracing.Start(ctx, "WordService.AddWords")
+	defer span.End()
+	span.SetAttributes(attribute.String("room", room))
+
+	if err := ValidateRoomName(room); err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, errors.New("No Words Sent")
+	}
+	if len(words) > MaxBatchSize {
+		return nil, fmt.Errorf("Too Many Words Sent (max %d)", MaxBatchSize)
+	}
+
+	for i, word := range words {
+		if err := ValidateWord(word); err != nil {
+			logging.FromContext(ctx, srv.logger).Error("Word is invalid.")
+			return nil, fmt.Errorf("Word %d: %s", i+1, err.Error())
+		}
+	}
+	for i, word := range words {
+		if err := srv.moderateWord(ctx, room, word); err != nil {
+			return nil, fmt.Errorf("Word %d: %w", i+1, err)
+		}
+	}
+
+	lock := srv.roomLock(room)
+	defer lock.Unlock()
+	srv.lockRoom(ctx, lock)
+
+	rm, err := srv.getRoom(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	if srv.voting(rm) {
+		return nil, ErrVotingBatch
+	}
+	if _, err := srv.checkSlot(room, ""); err != nil {
 		return nil, err
 	}
 
-	defer srv.Unlock()
-	// Adding two words concurrently might lead to inconsistency
-	srv.Lock()
+	results := make([]WordResponse, 0, len(words))
+	for _, word := range words {
+		wrdRes, err := srv.addWord(ctx, rm, word)
+		if err != nil {
+			// Words added so far stay added, return them along with the error.
+			return results, err
+		}
+		results = append(results, *wrdRes)
+	}
+	return results, nil
+}
+
+// Same as submitWord, in the Room's Slot if held (nil otherwise), which is released once the Word is added,
+// and only at the expected position (if any). Caller must hold the Room's lock.
+func (srv *WordService) submitWordWithOptions(ctx context.Context, room *Room, slot *Slot, opts WordOptions, word string) (*WordResponse, error) {
+	if slot != nil {
+		if err := srv.claimSlot(ctx, room, slot); err != nil {
+			return nil, err
+		}
+	}
+	if err := srv.checkPosition(ctx, room, opts.ExpectedPosition); err != nil {
+		return nil, err
+	}
+	wrdRes, err := srv.submitWord(ctx, room, word)
+	if err != nil {
+		return nil, err
+	}
+	if slot != nil {
+		srv.releaseSlot(room.Name)
+	}
+	return wrdRes, nil
+}
+
+// Adds a single (already validated) Word to a Room, or submits it as a candidate if the Room votes on Words.
+// Caller must hold the Room's lock.
+func (srv *WordService) submitWord(ctx context.Context, room *Room, word string) (*WordResponse, error) {
+	if srv.voting(room) {
+		return srv.addCandidate(ctx, room, NormalizeWord(word))
+	}
+	return srv.addWord(ctx, room, word)
+}
 
+// True if Room chooses its next Word by vote.
+func (srv *WordService) voting(room *Room) bool {
+	return srv.votes != nil && room.Rules.VoteSeconds > 0
+}
+
+// Adds a single (already validated) Word to a Room, caller must hold the Room's lock.
+func (srv *WordService) addWord(ctx context.Context, room *Room, word string) (*WordResponse, error) {
+	wrdRes, undo, err := srv.appendWord(ctx, room, NormalizeWord(word))
+	if err != nil {
+		return nil, err
+	}
+	for _, invalidator := range srv.invalidators {
+		invalidator.InvalidateStory(wrdRes.ID)
+	}
+	srv.countWord(undo)
+	srv.rememberWord(ctx, room.Name, wrdRes, undo)
+	return wrdRes, nil
+}
+
+// Appends a Word, returns what is needed to undo it (Story, Paragraph and Sentence as they were before).
+// The response has the next Word's position, worked out from what storage reports (see currentState).
+func (srv *WordService) appendWord(ctx context.Context, room *Room, word string) (*WordResponse, *WordUndo, error) {
 	// Find Unfinished Story
-	story, err := srv.storage.GetUnfinishedStory()
+	story, err := srv.storage.GetUnfinishedStory(ctx, room.Name)
 	if err != nil {
 		// No Unfinished Story, Create New Story
-		srv.logger.Info("Unfinished Story Not Found, Creating New Story...")
-		storyId, err := srv.storage.AddStory()
+		logging.FromContext(ctx, srv.logger).Info("Unfinished Story Not Found, Creating New Story...")
+		prompt := srv.nextPrompt(room)
+		if err := srv.moderateTitleWord(ctx, room, &Story{Room: room.Name, Prompt: prompt.Text, Keywords: prompt.Keywords}, word); err != nil {
+			return nil, nil, err
+		}
+		storyId, err := srv.storage.AddStory(ctx, room.Name, prompt)
 		if err != nil {
-			srv.logger.Error("Could Not Create New Story.")
-			return nil, err
+			logging.FromContext(ctx, srv.logger).Error("Could Not Create New Story.")
+			return nil, nil, err
 		}
+		srv.usePrompt(ctx, room)
+		ctx = logging.WithFields(ctx, log.Fields{"story_id": storyId})
 
 		// New Story will have blank title, Add Word to title.
-		if err := srv.storage.UpdateStoryTitle(storyId, word); err != nil {
-			srv.logger.Error("Could Not Update Story Title.")
-			return nil, err
+		if err := srv.storage.UpdateStoryTitle(ctx, storyId, word, room.Rules); err != nil {
+			logging.FromContext(ctx, srv.logger).Error("Could Not Update Story Title.")
+			return nil, nil, err
 		} else {
 			// Word Added To Story Title, Get Story By ID
-			story, err := srv.storage.GetStory(storyId)
+			story, err := srv.storage.GetStory(ctx, storyId)
 			if err != nil {
-				srv.logger.Error("Unexpected Error: Story Missing:" + err.Error())
-				return nil, err
+				logging.FromContext(ctx, srv.logger).WithError(err).Error("Unexpected Error: Story Missing")
+				return nil, nil, err
 			}
 			wrdRes := WordResponse{
 				ID:      story.ID,
+				Room:    story.Room,
 				Title:   story.Title,
 				Content: "",
+				Prompt:  story.Prompt,
+				// Where the next Word of the Room lands
+				NextPosition: positionAfterTitle(story),
 			}
-			return &wrdRes, nil
+			return &wrdRes, &WordUndo{StoryID: storyId, Version: story.Version}, nil
 		}
 	} else {
+		ctx = logging.WithFields(ctx, log.Fields{"story_id": story.ID})
+		before := *story
 		// Unfinished Story Found, Check if Story's Title is Finished.
 		if !story.TitleAdded {
 			// Add Word to Title instead.
-			if err := srv.storage.UpdateStoryTitle(story.ID, word); err != nil {
-				srv.logger.Error("Could Not Update Story Title.")
-				return nil, err
+			if err := srv.moderateTitleWord(ctx, room, story, word); err != nil {
+				return nil, nil, err
+			}
+			if err := srv.storage.UpdateStoryTitle(ctx, story.ID, word, room.Rules); err != nil {
+				logging.FromContext(ctx, srv.logger).Error("Could Not Update Story Title.")
+				return nil, nil, err
 			} else {
 				// Word Added To Story Title, Get Story By ID
-				story, err := srv.storage.GetStory(story.ID)
+				story, err := srv.storage.GetStory(ctx, story.ID)
 				if err != nil {
-					srv.logger.Error("Unexpected Error: Story Missing:" + err.Error())
-					return nil, err
+					logging.FromContext(ctx, srv.logger).WithError(err).Error("Unexpected Error: Story Missing")
+					return nil, nil, err
 				}
 				wrdRes := WordResponse{
 					ID:      story.ID,
+					Room:    story.Room,
 					Title:   story.Title,
 					Content: "",
+					Prompt:  story.Prompt,
+					// Where the next Word of the Room lands
+					NextPosition: positionAfterTitle(story),
 				}
-				return &wrdRes, nil
+				return &wrdRes, &WordUndo{StoryID: story.ID, Version: story.Version, Story: &before}, nil
 			}
 		}
 
 		// Story Title is already Finished, Find Unfinished Paragraph
-		paragraph, err := srv.storage.GetUnfinishedParagraph(story.ID)
+		paragraph, err := srv.storage.GetUnfinishedParagraph(ctx, story.ID)
 
 		if err != nil {
 			// No Unfinished Paragraph Found, Create New Paragraph
-			srv.logger.Info("Unfinished Paragraph Not Found, Creating New Paragraph...")
-			paragraphId, err := srv.storage.AddParagraph(story.ID)
+			logging.FromContext(ctx, srv.logger).Info("Unfinished Paragraph Not Found, Creating New Paragraph...")
+			paragraphId, err := srv.storage.AddParagraph(ctx, story.ID)
 			if err != nil {
 				// New Paragraph Cannot Be Created.
-				srv.logger.Error("Could Not Create New Paragraph.")
-				return nil, err
+				logging.FromContext(ctx, srv.logger).Error("Could Not Create New Paragraph.")
+				return nil, nil, err
 			}
 
 			// New Paragraph Created, Create a new Sentence (with Word).
-			if sentenceId, err := srv.storage.AddSentence(paragraphId, word); err != nil {
-				srv.logger.Error("Could Not Add New Sentence")
-				return nil, err
+			if update, err := srv.storage.AddSentence(ctx, paragraphId, word, room.Rules); err != nil {
+				logging.FromContext(ctx, srv.logger).Error("Could Not Add New Sentence")
+				return nil, nil, err
 			} else {
-				// Word Added To Sentence, Get Sentence By ID
-				sentence, err := srv.storage.GetSentence(sentenceId)
-				if err != nil {
-					srv.logger.Error("Unexpected Error: Sentence Missing:" + err.Error())
-					return nil, err
-				}
+				// Word Added To Sentence
 				wrdRes := WordResponse{
-					ID:      story.ID,
-					Title:   story.Title,
-					Content: sentence.Content,
+					ID:           story.ID,
+					Room:         story.Room,
+					Title:        story.Title,
+					Content:      update.Sentence.Content,
+					Prompt:       story.Prompt,
+					NextPosition: positionAfterSentence(story.ID, update),
 				}
-				return &wrdRes, nil
+				return &wrdRes, &WordUndo{StoryID: story.ID, Version: update.Version, Story: &before, NewParagraph: paragraphId, NewSentence: update.Sentence.ID, Update: update}, nil
 			}
 		} else {
 			// Unfinished Paragraph Found. Find Unfinished Sentence.
-			sentence, err := srv.storage.GetUnfinishedSentence(paragraph.ID)
+			sentence, err := srv.storage.GetUnfinishedSentence(ctx, paragraph.ID)
 			if err != nil {
 				// No Unfinished Sentence Found, Create New Sentence (with Word).
-				srv.logger.Info("Unfinished Sentence Not Found, Creating New Sentence...")
-				if sentenceId, err := srv.storage.AddSentence(paragraph.ID, word); err != nil {
-					srv.logger.Error("Could Not Add New Sentence")
-					return nil, err
+				logging.FromContext(ctx, srv.logger).Info("Unfinished Sentence Not Found, Creating New Sentence...")
+				if update, err := srv.storage.AddSentence(ctx, paragraph.ID, word, room.Rules); err != nil {
+					logging.FromContext(ctx, srv.logger).Error("Could Not Add New Sentence")
+					return nil, nil, err
 				} else {
-					// Word Added To New Sentence. Get Sentence By ID
-					sentence, err := srv.storage.GetSentence(sentenceId)
-					if err != nil {
-						srv.logger.Error("Unexpected Error: Sentence Missing:" + err.Error())
-						return nil, err
-					}
+					// Word Added To New Sentence.
 					wrdRes := WordResponse{
-						ID:      story.ID,
-						Title:   story.Title,
-						Content: sentence.Content,
+						ID:           story.ID,
+						Room:         story.Room,
+						Title:        story.Title,
+						Content:      update.Sentence.Content,
+						Prompt:       story.Prompt,
+						NextPosition: positionAfterSentence(story.ID, update),
 					}
-					return &wrdRes, nil
+					return &wrdRes, &WordUndo{StoryID: story.ID, Version: update.Version, Story: &before, Paragraph: paragraph.ID, NewSentence: update.Sentence.ID, Update: update}, nil
 				}
 			} else {
+				sentenceBefore := *sentence
 				// Unfinished Sentence Found, Add Word to Sentence
-				if err := srv.storage.UpdateSentence(sentence.ID, word); err != nil {
-					srv.logger.Error("Could Not Update Sentence.")
-					return nil, err
+				if update, err := srv.storage.UpdateSentence(ctx, sentence.ID, word, room.Rules); err != nil {
+					logging.FromContext(ctx, srv.logger).Error("Could Not Update Sentence.")
+					return nil, nil, err
 				} else {
-					// Word Added To Existing Sentence. Get Sentence By ID
-					sentence, err := srv.storage.GetSentence(sentence.ID)
-					if err != nil {
-						srv.logger.Error("Unexpected Error: Sentence Missing:" + err.Error())
-						return nil, err
-					}
+					// Word Added To Existing Sentence.
 					wrdRes := WordResponse{
-						ID:      story.ID,
-						Title:   story.Title,
-						Content: sentence.Content,
+						ID:           story.ID,
+						Room:         story.Room,
+						Title:        story.Title,
+						Content:      update.Sentence.Content,
+						Prompt:       story.Prompt,
+						NextPosition: positionAfterSentence(story.ID, update),
 					}
-					return &wrdRes, nil
+					return &wrdRes, &WordUndo{StoryID: story.ID, Version: update.Version, Story: &before, Paragraph: paragraph.ID, Sentence: &sentenceBefore, Update: update}, nil
 				}
 			}
 			// NOTE: Updating a Sentence will take care of updating both Paragraph and Story as finished if they are.
@@ -219,7 +635,7 @@ Add Word
 				N - Create New Paragraph - New Sentence - Add Word
 				Y - Find Unfinished Sentence
 					N - Create New Sentence - Add Word
-					Y - Add Word - Check if Sentence Full (15 Words)
+					Y - Add Word - Check if Sentence Full (15 Words, or ended by . ! ? after MinSentenceWords)
 						N - Finished.
 						Y - Mark Sentence Finished - Check If Paragraph Full (10 Sentences)
 							N - Finished.
@@ -1,9 +1,21 @@
 package word
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	. "github.com/shubhamdwivedii/collab-story/pkg/sentence"
+	. "github.com/shubhamdwivedii/collab-story/pkg/story"
+	"github.com/shubhamdwivedii/collab-story/pkg/tracing"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestValidateWord(t *testing.T) {
@@ -27,4 +39,193 @@ func TestValidateWord(t *testing.T) {
 
 	err = ValidateWord("InvalidLengthOfTheWord")
 	require.Error(t, err)
+
+	err = ValidateWord("SixteenCharWord!?!")
+	require.NoError(t, err, "Expected Trailing Punctuation Not To Count Towards Length")
+
+	err = ValidateWord("end!!!!")
+	require.Error(t, err)
+
+	err = ValidateWord(",")
+	require.NoError(t, err)
+}
+
+func TestValidateUnicodeWord(t *testing.T) {
+	err := ValidateWord("नमस्ते")
+	require.NoError(t, err, "Expected Length To Count Characters, Not Bytes")
+
+	err = ValidateWord("こんにちは世界")
+	require.NoError(t, err, "Expected Japanese Scripts To Mix")
+
+	err = ValidateWord("ПриветМирПриветМ")
+	require.NoError(t, err)
+
+	err = ValidateWord("ПриветМирПриветМи")
+	require.Error(t, err)
+
+	err = ValidateWord("cafe\u0301")
+	require.NoError(t, err, "Expected Combining Mark Not To Count As A Character")
+
+	err = ValidateWord("zero\u200Bwidth")
+	require.Error(t, err)
+
+	err = ValidateWord("join\u200Ded")
+	require.Error(t, err)
+
+	err = ValidateWord("bell\u0007")
+	require.Error(t, err)
+
+	err = ValidateWord("no\u00A0break")
+	require.Error(t, err)
+
+	err = ValidateWord("\u3164")
+	require.Error(t, err)
+
+	err = ValidateWord("p\u0430ypal") // Cyrillic "а"
+	require.Error(t, err, "Expected Mixed Script Word To Be Rejected")
+
+	err = ValidateWord("invalid\xff")
+	require.Error(t, err)
+}
+
+func FuzzValidateWord(f *testing.F) {
+	for _, seed := range []string{"validword", "end.", "नमस्ते", "こんにちは", "cafe\u0301", "join\u200Ded", "p\u0430ypal", ",", ""} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, word string) {
+		if err := ValidateWord(word); err != nil {
+			return
+		}
+		// A valid Word must stay valid once normalized for storage.
+		normalized := NormalizeWord(word)
+		require.NoError(t, ValidateWord(normalized), "Normalized %q To Invalid %q", word, normalized)
+		require.True(t, utf8.ValidString(normalized))
+		for _, r := range normalized {
+			require.False(t, isSpace(r) || isInvisible(r), "Valid Word %q Contains %U", word, r)
+		}
+	})
+}
+
+func TestAddWordsValidation(t *testing.T) {
+	// Words are validated before Storage is touched, so no Storage is needed.
+	srv := NewWordService(nil, log.New())
+
+	_, err := srv.AddWords(context.Background(), DefaultRoom, []string{})
+	require.Error(t, err)
+
+	_, err = srv.AddWords(context.Background(), DefaultRoom, []string{"valid", "in valid"})
+	require.Error(t, err)
+
+	_, err = srv.AddWords(context.Background(), DefaultRoom, make([]string, MaxBatchSize+1))
+	require.Error(t, err)
+
+	_, err = srv.AddWords(context.Background(), "Invalid Room", []string{"valid"})
+	require.Equal(t, ErrInvalidRoomName, err)
+}
+
+func TestAddWordSpans(t *testing.T) {
+	storage := writingStoryStorage()
+	srv := NewWordService(storage, log.New())
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx, root := provider.Tracer(tracing.Instrumentation).Start(context.Background(), "POST /add")
+
+	_, err := srv.AddWord(ctx, DefaultRoom, "there")
+	require.NoError(t, err)
+	root.End()
+
+	var names []string
+	for _, span := range exporter.GetSpans() {
+		names = append(names, span.Name)
+	}
+	require.Equal(t, []string{"WordService.lockRoom", "WordService.AddWord", "POST /add"}, names)
+	spans := exporter.GetSpans()
+	require.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID(), "Expected Lock Wait Within AddWord")
+	require.Equal(t, spans[2].SpanContext.SpanID(), spans[1].Parent.SpanID())
+}
+
+func TestSetDefaultRules(t *testing.T) {
+	storage := NewMemoryStorage()
+	srv := NewWordService(storage, log.New())
+	rules := DefaultStoryRules
+	rules.TitleWords = 1
+	srv.SetDefaultRules(rules)
+
+	_, err := srv.AddWord(context.Background(), DefaultRoom, "Ahoy")
+	require.NoError(t, err)
+	story, err := storage.GetUnfinishedStory(context.Background(), DefaultRoom)
+	require.NoError(t, err)
+	require.True(t, story.TitleAdded, "Expected Rooms That Were Never Configured To Use The Rules Set")
+	require.Equal(t, int32(2), DefaultStoryRules.TitleWords)
+}
+
+// IdempotencyStorage keeping responses by Room and key.
+type mapIdempotencyStorage map[string]IdempotentResponse
+
+func (s mapIdempotencyStorage) GetIdempotentResponse(ctx context.Context, room string, key string, since time.Time) (*IdempotentResponse, error) {
+	idemRes, ok := s[room+"/"+key]
+	if !ok {
+		return nil, ErrIdempotencyKeyNotFound
+	}
+	return &idemRes, nil
+}
+
+func (s mapIdempotencyStorage) SaveIdempotentResponse(ctx context.Context, idemRes IdempotentResponse) error {
+	s[idemRes.Room+"/"+idemRes.Key] = idemRes
+	return nil
+}
+
+func TestAddWordWithKeyPerRoomAndRequest(t *testing.T) {
+	srv := NewWordService(NewMemoryStorage(), log.New())
+	srv.EnableIdempotency(mapIdempotencyStorage{}, time.Hour)
+	ctx := context.Background()
+	hash := HashRequest([]byte(`{"word":"Once"}`))
+
+	_, replayed, err := srv.AddWordWithKey(ctx, DefaultRoom, "key", hash, "Once", WordOptions{})
+	require.NoError(t, err)
+	require.False(t, replayed)
+	_, replayed, err = srv.AddWordWithKey(ctx, DefaultRoom, "key", hash, "Once", WordOptions{})
+	require.NoError(t, err)
+	require.True(t, replayed, "Expected A Retry To Be Replayed")
+
+	wrdRes, replayed, err := srv.AddWordWithKey(ctx, "other", "key", hash, "Once", WordOptions{})
+	require.NoError(t, err)
+	require.False(t, replayed, "Expected The Same Key To Add A Word In Another Room")
+	require.Equal(t, "other", wrdRes.Room)
+
+	// Same Word, but a different request (e.g. another expected position).
+	_, _, err = srv.AddWordWithKey(ctx, DefaultRoom, "key", HashRequest([]byte(`{"word":"Once","expected_position":{}}`)), "Once", WordOptions{})
+	require.Equal(t, ErrIdempotencyKeyReused, err)
+}
+
+func TestRoomLocksAreBounded(t *testing.T) {
+	srv := NewWordService(nil, log.New())
+	require.Same(t, srv.roomLock(DefaultRoom), srv.roomLock(DefaultRoom))
+
+	locks := make(map[*sync.Mutex]bool)
+	for i := 0; i < 10*roomLockShards; i++ {
+		locks[srv.roomLock(fmt.Sprintf("room-%d", i))] = true
+	}
+	require.LessOrEqual(t, len(locks), roomLockShards, "Expected Room Names Not To Add Locks")
+}
+
+func TestCheckLockWait(t *testing.T) {
+	srv := NewWordService(nil, log.New())
+	require.NoError(t, srv.CheckLockWait(context.Background(), time.Millisecond))
+
+	lock := srv.roomLock(DefaultRoom)
+	lock.Lock()
+	locked := make(chan struct{})
+	go func() {
+		srv.lockRoom(context.Background(), lock)
+		lock.Unlock()
+		close(locked)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	require.True(t, errors.Is(srv.CheckLockWait(context.Background(), 10*time.Millisecond), ErrLockStarvation))
+
+	lock.Unlock()
+	<-locked
+	require.NoError(t, srv.CheckLockWait(context.Background(), 10*time.Millisecond))
 }